@@ -0,0 +1,103 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// errorCollector collects errors during Execute, see WithAggregatedErrors.
+type errorCollector struct {
+	errs []error
+	// reported is set as soon as the collected errors have been returned during validate.
+	reported bool
+}
+
+func (c *errorCollector) add(err error) {
+	c.errs = append(c.errs, err)
+}
+
+func (c *errorCollector) addFlagError(flagName string, source flag.Source, err error) {
+	c.add(fmt.Errorf("flag --%s (from %s): %w", flagName, source, err))
+}
+
+// addMissingRequiredFlags mimics [cobra.Command.ValidateRequiredFlags] but reports each missing flag individually.
+func (c *errorCollector) addMissingRequiredFlags(cmd *cobra.Command) {
+	if cmd.DisableFlagParsing {
+		return
+	}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if annotation, found := flag.Annotations[cobra.BashCompOneRequiredFlag]; found &&
+			len(annotation) > 0 && annotation[0] == "true" && !flag.Changed {
+			c.add(fmt.Errorf("flag --%s: required but not set", flag.Name))
+		}
+	})
+}
+
+// join joins the collected errors with the given error from execution,
+// unless the collected errors have already been reported.
+func (c *errorCollector) join(err error) error {
+	if c.reported || len(c.errs) == 0 {
+		return err
+	}
+	return errors.Join(append(c.errs, err)...)
+}
+
+// collectingValue replaces the value of flags during Execute, see installErrorCollector.
+type collectingValue struct {
+	flag.Value
+
+	flagName  string
+	collector *errorCollector
+}
+
+// Set never fails but collects the error.
+func (v collectingValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		v.collector.addFlagError(v.flagName, flag.SourceFlag, fmt.Errorf("invalid argument %q: %w", s, err))
+	}
+	return nil
+}
+
+// Unwrap implements flag.WrappingValue.
+func (v collectingValue) Unwrap() flag.Value {
+	return v.Value
+}
+
+// CollectBindingError implements flag.BindingErrorCollector.
+func (v collectingValue) CollectBindingError(err error) {
+	var errWithSource flag.SourceError
+	if errors.As(err, &errWithSource) {
+		v.collector.addFlagError(v.flagName, errWithSource.Source, err)
+	} else {
+		v.collector.add(fmt.Errorf("flag --%s: %w", v.flagName, err))
+	}
+}
+
+// installErrorCollector replaces all flag values of this command and all sub commands
+// with collectingValue. The returned function restores the previous state.
+func (c Command) installErrorCollector(collector *errorCollector) (restore func()) {
+	replaced := map[*pflag.Flag]pflag.Value{}
+	replaceValue := func(f *pflag.Flag) {
+		if _, alreadyReplaced := replaced[f]; alreadyReplaced {
+			return
+		}
+		// only flags registered via Command.Flag are supported
+		if value, ok := f.Value.(flag.Value); ok {
+			replaced[f] = f.Value
+			f.Value = collectingValue{value, f.Name, collector}
+		}
+	}
+	for command := range c.All() {
+		command.Flags().VisitAll(replaceValue)
+		command.PersistentFlags().VisitAll(replaceValue)
+	}
+	return func() {
+		for f, value := range replaced {
+			f.Value = value
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/neiser/go-nagini/flag/binding"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAggregatedErrors(t *testing.T) {
+	viper.AutomaticEnv()
+
+	var (
+		someInt      int
+		someInts     []int
+		someFromEnv  int
+		someRequired string
+	)
+	cmd := New().
+		Flag(flag.String(&someInt, strconv.Atoi), flag.RegisterOptions{Name: "some-int"}).
+		Flag(flag.Slice(&someInts, flag.ParseSliceOf(strconv.Atoi)), flag.RegisterOptions{Name: "some-ints"}).
+		Flag(
+			binding.Viper{
+				Value:     flag.String(&someFromEnv, strconv.Atoi),
+				ConfigKey: "SOME_AGGREGATED_INT",
+			},
+			flag.RegisterOptions{Name: "some-from-env", Persistent: true},
+		).
+		Flag(flag.String(&someRequired, flag.NotEmpty), flag.RegisterOptions{Name: "some-required", Required: true}).
+		Validate(func() error {
+			return errors.New("some validation error")
+		}).
+		Run(func() error {
+			t.Fatal("should never be called")
+			return nil
+		})
+	cmd.CaptureCobraOutput(t) // avoid confusing test output
+
+	t.Setenv("SOME_AGGREGATED_INT", "x1x")
+
+	t.Run("all errors are reported", func(t *testing.T) {
+		err := cmd.Execute(
+			WithArgs("--some-int", "x2x", "--some-ints", "3,x4x"),
+			WithAggregatedErrors(),
+			AssertExitCode(t, 1),
+		)
+		require.EqualError(t, err, `flag --some-int (from flag): invalid argument "x2x": strconv.Atoi: parsing "x2x": invalid syntax
+flag --some-ints (from flag): invalid argument "3,x4x": cannot parse slice element 1: strconv.Atoi: parsing "x4x": invalid syntax
+flag --some-from-env (from env): cannot set value to viper config SOME_AGGREGATED_INT='x1x': strconv.Atoi: parsing "x1x": invalid syntax
+flag --some-required: required but not set
+some validation error`)
+	})
+
+	t.Run("flag values are restored", func(t *testing.T) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			assert.IsNotType(t, collectingValue{}, f.Value)
+		})
+	})
+
+	t.Run("without aggregation stops at first error", func(t *testing.T) {
+		require.EqualError(t,
+			cmd.Execute(WithArgs("--some-int", "x2x", "--some-ints", "3,x4x"), AssertExitCode(t, 1)),
+			`invalid argument "x2x" for "--some-int" flag: strconv.Atoi: parsing "x2x": invalid syntax`,
+		)
+	})
+
+	t.Run("errors are reported even if command is not run", func(t *testing.T) {
+		err := cmd.Execute(
+			WithArgs("--some-int", "x2x", "--unknown"),
+			WithAggregatedErrors(),
+			AssertExitCode(t, 1),
+		)
+		require.EqualError(t, err, `flag --some-int (from flag): invalid argument "x2x": strconv.Atoi: parsing "x2x": invalid syntax
+unknown flag: --unknown`)
+	})
+}
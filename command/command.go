@@ -2,7 +2,9 @@
 package command
 
 import (
+	"fmt"
 	"iter"
+	"unsafe"
 
 	"github.com/neiser/go-nagini/flag"
 	"github.com/spf13/cobra"
@@ -28,22 +30,44 @@ type Command struct {
 
 	// flagNames holds the registered flag names for a command,
 	// identified by the target pointer as the map key.
-	flagNames map[uintptr][]string
+	flagNames map[unsafe.Pointer][]string
+	// validators holds the callbacks added via Validate, see there.
+	// Uses a pointer to slice to enable Command value modification after construction.
+	validators *[]func() error
+	// confirmations holds the callbacks added via RequireConfirmation, see there.
+	// Uses a pointer to slice to enable Command value modification after construction.
+	confirmations *[]func(cmd *cobra.Command, prompter *Prompter) error
+	// constraints holds the flag constraints applied during Execute, see MarkFlagsMutuallyExclusive.
+	// Uses a pointer to slice to enable Command value modification after construction.
+	constraints *[]*flagConstraint
+	// resetters restore the registration-time values of the flag targets, see Reset.
+	// Uses a pointer to slice to enable Command value modification after construction.
+	resetters *[]func()
+	// resetOnExecute makes Execute reset the flags of this command before execution, see NewTyped.
+	resetOnExecute bool
 }
 
 // New constructs a command.
 func New() Command {
 	var noParent *Command
 	var noCommands []Command
+	var noValidators []func() error
+	var noResetters []func()
+	var noConstraints []*flagConstraint
+	var noConfirmations []func(cmd *cobra.Command, prompter *Prompter) error
 	return Command{
-		&cobra.Command{
+		Command: &cobra.Command{
 			// We do our own usage output in Command.Execute below.
 			SilenceErrors: true,
 			SilenceUsage:  true,
 		},
-		&noCommands,
-		&noParent,
-		map[uintptr][]string{},
+		commands:      &noCommands,
+		parent:        &noParent,
+		flagNames:     map[unsafe.Pointer][]string{},
+		validators:    &noValidators,
+		resetters:     &noResetters,
+		constraints:   &noConstraints,
+		confirmations: &noConfirmations,
 	}
 }
 
@@ -76,7 +100,25 @@ func (c Command) Flag(flagValue flag.Value, options flag.RegisterOptions) Comman
 	flags := options.SelectFlags(c.Command)
 	newFlag := flags.VarPF(flagValue, options.Name, options.Shorthand, options.Usage)
 	c.addFlagName(flagValue.Target(), options.Name)
+	c.addResetter(flagValue.Target())
 	options.AfterRegistration(c.Command, newFlag, flagValue)
+	if options.Group != "" || options.Negatable {
+		c.enableFlagSections()
+	}
+	return c
+}
+
+// FlagsFrom registers one flag per field of the struct the given target points to,
+// see [flag.FromStruct] for the supported struct tags and field types.
+// Panics if the struct is not supported.
+func (c Command) FlagsFrom(target any, options ...flag.StructOption) Command {
+	fields, err := flag.FromStruct(target, options...)
+	if err != nil {
+		panic(fmt.Sprintf("cannot register flags from struct: %v", err))
+	}
+	for _, field := range fields {
+		c = c.Flag(field.Value, field.Options)
+	}
 	return c
 }
 
@@ -101,11 +143,21 @@ func (c Command) Run(run func() error) Command {
 // AddPersistentPreRun adds the given code to run persistently, that means it will be executed
 // also for sub commands added with AddCommands.
 // Pre-runs before PreRun of command itself and the Run of the command.
+// Execute runs the persistent pre-runs of all parents from the root down to the executed command,
+// even if sub commands have their own.
 func (c Command) AddPersistentPreRun(run func() error) Command {
 	c.addToPersistentPreRunE(wrapRunCallbackError(run))
 	return c
 }
 
+// AddPersistentPostRun adds the given code to run persistently after the Run of the command,
+// that means it will be executed also for sub commands added with AddCommands.
+// Execute runs the persistent post-runs from the executed command up to the root.
+func (c Command) AddPersistentPostRun(run func() error) Command {
+	c.PersistentPostRunE = chainCobraRun(c.PersistentPostRunE, wrapRunCallbackError(run))
+	return c
+}
+
 // All returns this command and all sub-commands added via AddCommands recursively as an iterator.
 func (c Command) All() iter.Seq[Command] {
 	return c.all
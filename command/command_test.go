@@ -10,6 +10,8 @@ import (
 
 	"github.com/neiser/go-nagini/flag"
 	"github.com/neiser/go-nagini/flag/binding"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -480,3 +482,128 @@ func mapSequence[T, U any](items iter.Seq[T], mapper func(T) U) iter.Seq[U] {
 		}
 	}
 }
+
+func TestCommand_FlagsFrom(t *testing.T) {
+	type options struct {
+		Name    string `short:"n" usage:"The name" required:"true"`
+		Retries int    `env:"SOME_FLAGS_FROM_RETRIES"`
+		Format  string `enum:"text,json"`
+		DB      struct {
+			Host string `config:"some-flags-from-db-host"`
+		}
+	}
+
+	t.Run("flags are registered and bound", func(t *testing.T) {
+		viper.Set("some-flags-from-db-host", "localhost")
+		t.Cleanup(viper.Reset)
+		t.Setenv("SOME_FLAGS_FROM_RETRIES", "3")
+		opts := options{Format: "text"}
+		cmd := New().FlagsFrom(&opts, binding.ViperConfig()).MarkFlagsMutuallyExclusive(&opts.Retries, &opts.Format)
+		require.NoError(t, cmd.Execute(WithArgs("-n", "some name"),
+			AssertExitCode(t, 0),
+			AssertWithRun(t, func() {
+				assert.Equal(t, "some name", opts.Name)
+				assert.Equal(t, 3, opts.Retries)
+				assert.Equal(t, "text", opts.Format)
+				assert.Equal(t, "localhost", opts.DB.Host)
+			}),
+		))
+		assert.Equal(t, "The name", cmd.Flags().Lookup("name").Usage)
+		assert.NotNil(t, cmd.Flags().Lookup("db-host"))
+	})
+
+	t.Run("required flag", func(t *testing.T) {
+		var opts options
+		cmd := New().FlagsFrom(&opts, binding.ViperConfig()).Run(func() error {
+			return nil
+		})
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, cmd.Execute(WithArgs(), AssertExitCode(t, 1)), `required flag(s) "name" not set`)
+	})
+
+	t.Run("unsupported struct panics", func(t *testing.T) {
+		var opts options
+		assert.PanicsWithValue(t, "cannot register flags from struct: field DB: field Host: "+
+			"invalid struct for flags: config tag requires WithConfigBinding", func() {
+			New().FlagsFrom(&opts)
+		})
+	})
+}
+
+func TestCommand_negatableFlag(t *testing.T) {
+	var (
+		color   = true
+		verbose bool
+	)
+	sub := New().Use("sub").
+		Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Usage: "Be verbose"}).
+		Run(func() error { return nil })
+	root := New().Use("app").
+		Flag(flag.Bool(&color), flag.RegisterOptions{Name: "color", Usage: "Use colors", Persistent: true}.Apply(flag.Negatable())).
+		AddCommands(sub)
+
+	t.Run("negation", func(t *testing.T) {
+		require.NoError(t, root.Execute(WithArgs("sub", "--no-color"), WithReset(), AssertExitCode(t, 0)))
+		assert.False(t, color)
+	})
+
+	t.Run("conflicting use is usage error", func(t *testing.T) {
+		root.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorIs(t, root.Execute(WithArgs("sub", "--color", "--no-color"), WithReset(), AssertExitCode(t, 1)),
+			flag.ErrNegationConflict)
+	})
+
+	t.Run("help output", func(t *testing.T) {
+		getStdout, _ := root.CaptureCobraOutput(t)
+		require.NoError(t, root.Execute(WithArgs("sub", "--help"), WithReset(), AssertExitCode(t, 0)))
+		assert.Equal(t, `Usage:
+  app sub [flags]
+
+Flags:
+  -h, --help             help for sub
+      --verbose[=true]   Be verbose
+
+Global Flags:
+      --[no-]color     Use colors (default true)
+`, getStdout())
+	})
+}
+
+func TestCommand_FlagCompletion(t *testing.T) {
+	var (
+		format, config, region, zone string
+	)
+	cmd := New().Use("app").
+		Flag(flag.Enum(&format, "text", "json"), flag.RegisterOptions{Name: "format"}.Apply(flag.WithAliases("output"))).
+		Flag(flag.String(&config, flag.NotEmpty), flag.RegisterOptions{Name: "config", Complete: flag.CompleteFileExtensions("yaml")}).
+		Flag(flag.String(&region, flag.NotEmpty), flag.RegisterOptions{Name: "region", Complete: flag.CompleteValues("eu\tEurope", "us")}).
+		Flag(flag.String(&zone, flag.NotEmpty), flag.RegisterOptions{
+			Name: "zone",
+			Complete: func(toComplete string, flags *pflag.FlagSet) ([]string, cobra.ShellCompDirective) {
+				return []string{flags.Lookup("region").Value.String() + "-" + toComplete}, cobra.ShellCompDirectiveNoFileComp
+			},
+		}).
+		Run(func() error { return nil })
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return []cobra.Completion{"arg" + strconv.Itoa(len(args))}, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	for _, tt := range []struct {
+		args              []string
+		expected          []Completion
+		expectedDirective cobra.ShellCompDirective
+	}{
+		{[]string{"--format", ""}, []Completion{{"text", ""}, {"json", ""}}, cobra.ShellCompDirectiveNoFileComp},
+		{[]string{"--output", ""}, []Completion{{"text", ""}, {"json", ""}}, cobra.ShellCompDirectiveNoFileComp},
+		{[]string{"--config", ""}, []Completion{{"yaml", ""}}, cobra.ShellCompDirectiveFilterFileExt},
+		{[]string{"--region", ""}, []Completion{{"eu", "Europe"}, {"us", ""}}, cobra.ShellCompDirectiveNoFileComp},
+		{[]string{"--region", "eu", "--zone", "1"}, []Completion{{"eu-1", ""}}, cobra.ShellCompDirectiveNoFileComp},
+		{[]string{"first", ""}, []Completion{{"arg1", ""}}, cobra.ShellCompDirectiveNoFileComp},
+	} {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			completions, directive := cmd.CaptureCompletions(t, tt.args...)
+			assert.Equal(t, tt.expected, completions)
+			assert.Equal(t, tt.expectedDirective, directive)
+		})
+	}
+}
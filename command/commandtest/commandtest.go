@@ -0,0 +1,203 @@
+// Package commandtest executes a command.Command in-process for testing and asserts on its outcome.
+// In contrast to the helpers in package command, it does not depend on testify.
+package commandtest
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/spf13/cobra"
+)
+
+// Result is the outcome of Run, providing fluent assertions.
+type Result struct {
+	t testing.TB
+
+	// Stdout is the output of the command written to cobra.Command.OutOrStdout.
+	Stdout string
+	// Stderr is the output of the command written to cobra.Command.ErrOrStderr.
+	Stderr string
+	// ExitCode is the exit code passed to the exiter, see command.WithExiter.
+	ExitCode int
+	// Err is the error returned by command.Command.Execute.
+	Err error
+	// LoggedErrors are the errors logged during execution, see command.WithErrorLogger.
+	LoggedErrors []error
+}
+
+// Run executes the given command with the given arguments, see RunWithOptions.
+func Run(t testing.TB, cmd command.Command, args ...string) Result {
+	t.Helper()
+	return RunWithOptions(t, cmd, command.WithArgs(args...))
+}
+
+// RunWithOptions executes the given command with the given command.ExecuteOption's
+// and captures the output, exit code, returned error and logged errors.
+// Use command.WithArgs to set the arguments.
+// The output writers, arguments and run callbacks of the whole command tree are restored after execution.
+// Note that the output writers are restored with their effective value, as Cobra does not expose unset writers.
+func RunWithOptions(t testing.TB, cmd command.Command, options ...command.ExecuteOption) Result {
+	t.Helper()
+	restore := saveState(cmd)
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	result := Result{t: t, ExitCode: -1}
+	exitCodeCaptured := false
+	options = append(options,
+		command.WithExiter(func(exitCode int) {
+			exitCodeCaptured = true
+			result.ExitCode = exitCode
+		}),
+		command.WithErrorLogger(func(err error) {
+			result.LoggedErrors = append(result.LoggedErrors, err)
+		}),
+	)
+	result.Err = cmd.Execute(options...)
+	if !exitCodeCaptured {
+		t.Errorf("exiter was not run")
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	return result
+}
+
+// saveState saves the state of the command tree which is possibly modified during Run.
+func saveState(cmd command.Command) (restore func()) {
+	type runCallbacks struct {
+		preRunE, runE, postRunE, persistentPreRunE, persistentPostRunE func(*cobra.Command, []string) error
+	}
+	saved := map[*cobra.Command]runCallbacks{}
+	for c := range cmd.All() {
+		saved[c.Command] = runCallbacks{c.PreRunE, c.RunE, c.PostRunE, c.PersistentPreRunE, c.PersistentPostRunE}
+	}
+	stdout, stderr := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	args := getArgs(cmd.Command)
+	return func() {
+		for c, callbacks := range saved {
+			c.PreRunE = callbacks.preRunE
+			c.RunE = callbacks.runE
+			c.PostRunE = callbacks.postRunE
+			c.PersistentPreRunE = callbacks.persistentPreRunE
+			c.PersistentPostRunE = callbacks.persistentPostRunE
+		}
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SetArgs(args)
+	}
+}
+
+// getArgs returns the arguments set with [cobra.Command.SetArgs], which may be nil.
+// Reads the unexported field via reflection, as Cobra does not offer a getter.
+func getArgs(cmd *cobra.Command) []string {
+	field := reflect.ValueOf(cmd).Elem().FieldByName("args")
+	if !field.IsValid() || field.Kind() != reflect.Slice || field.IsNil() {
+		return nil
+	}
+	args := make([]string, field.Len())
+	for i := range args {
+		args[i] = field.Index(i).String()
+	}
+	return args
+}
+
+// ExpectSuccess asserts that the command exited with exit code 0 without any error.
+func (r Result) ExpectSuccess() Result {
+	r.t.Helper()
+	r.ExpectExitCode(0)
+	if r.Err != nil {
+		r.t.Errorf("expected no error, but got: %v", r.Err)
+	}
+	return r
+}
+
+// ExpectExitCode asserts the exit code.
+func (r Result) ExpectExitCode(exitCode int) Result {
+	r.t.Helper()
+	if r.ExitCode != exitCode {
+		r.t.Errorf("expected exit code %d, but got %d", exitCode, r.ExitCode)
+	}
+	return r
+}
+
+// ExpectErrorContains asserts that the returned error contains the given message.
+func (r Result) ExpectErrorContains(message string) Result {
+	r.t.Helper()
+	switch {
+	case r.Err == nil:
+		r.t.Errorf("expected error containing %q, but got no error", message)
+	case !strings.Contains(r.Err.Error(), message):
+		r.t.Errorf("expected error containing %q, but got: %v", message, r.Err)
+	}
+	return r
+}
+
+// ExpectErrorIs asserts that the returned error matches the given target using [errors.Is].
+func (r Result) ExpectErrorIs(target error) Result {
+	r.t.Helper()
+	if !errors.Is(r.Err, target) {
+		r.t.Errorf("expected error matching %v, but got: %v", target, r.Err)
+	}
+	return r
+}
+
+// ExpectLoggedErrorIs asserts that at least one of the logged errors matches the given target using [errors.Is].
+func (r Result) ExpectLoggedErrorIs(target error) Result {
+	r.t.Helper()
+	for _, err := range r.LoggedErrors {
+		if errors.Is(err, target) {
+			return r
+		}
+	}
+	r.t.Errorf("expected logged error matching %v, but got: %v", target, r.LoggedErrors)
+	return r
+}
+
+// ExpectStdout asserts that the standard output equals the given output.
+func (r Result) ExpectStdout(output string) Result {
+	r.t.Helper()
+	expectOutput(r.t, "stdout", r.Stdout, output)
+	return r
+}
+
+// ExpectStdoutContains asserts that the standard output contains the given output.
+func (r Result) ExpectStdoutContains(output string) Result {
+	r.t.Helper()
+	expectOutputContains(r.t, "stdout", r.Stdout, output)
+	return r
+}
+
+// ExpectStderr asserts that the error output equals the given output.
+func (r Result) ExpectStderr(output string) Result {
+	r.t.Helper()
+	expectOutput(r.t, "stderr", r.Stderr, output)
+	return r
+}
+
+// ExpectStderrContains asserts that the error output contains the given output.
+func (r Result) ExpectStderrContains(output string) Result {
+	r.t.Helper()
+	expectOutputContains(r.t, "stderr", r.Stderr, output)
+	return r
+}
+
+func expectOutput(t testing.TB, name, actual, expected string) {
+	t.Helper()
+	if actual != expected {
+		t.Errorf("expected %s to be\n%s\nbut got\n%s", name, expected, actual)
+	}
+}
+
+func expectOutputContains(t testing.TB, name, actual, expected string) {
+	t.Helper()
+	if !strings.Contains(actual, expected) {
+		t.Errorf("expected %s to contain\n%s\nbut got\n%s", name, expected, actual)
+	}
+}
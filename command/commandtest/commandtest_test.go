@@ -0,0 +1,114 @@
+package commandtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSome = errors.New("some error")
+
+func newSomeCommand() command.Command {
+	var (
+		someVal string
+	)
+	cmd := command.New().Use("app")
+	return cmd.
+		Flag(flag.String(&someVal, flag.NotEmpty), flag.RegisterOptions{Name: "some-val", Required: true}).
+		Run(func() error {
+			cmd.Printf("got %s", someVal)
+			if someVal == "fail" {
+				return command.WithExitCodeError{ExitCode: 3, Wrapped: errSome}
+			}
+			return nil
+		})
+}
+
+func TestRun(t *testing.T) {
+	cmd := newSomeCommand()
+	runE := cmd.RunE
+
+	t.Run("success", func(t *testing.T) {
+		result := Run(t, cmd, "--some-val", "bla").
+			ExpectSuccess().
+			ExpectStdout("got bla").
+			ExpectStderr("")
+		assert.Empty(t, result.LoggedErrors)
+	})
+
+	t.Run("run error is logged", func(t *testing.T) {
+		result := Run(t, cmd, "--some-val", "fail").
+			ExpectExitCode(3).
+			ExpectErrorIs(errSome).
+			ExpectLoggedErrorIs(errSome).
+			ExpectStdout("got fail")
+		assert.Len(t, result.LoggedErrors, 1)
+	})
+
+	t.Run("usage error", func(t *testing.T) {
+		result := Run(t, newSomeCommand()).
+			ExpectExitCode(1).
+			ExpectErrorContains(`required flag(s) "some-val" not set`).
+			ExpectStderrContains(`Error: required flag(s) "some-val" not set`).
+			ExpectStdoutContains("Usage:")
+		assert.Empty(t, result.LoggedErrors)
+	})
+
+	t.Run("state is restored", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%p", runE), fmt.Sprintf("%p", cmd.RunE))
+		assert.Same(t, os.Stdout, cmd.OutOrStdout())
+		assert.Same(t, os.Stderr, cmd.ErrOrStderr())
+		assert.Nil(t, getArgs(cmd.Command))
+	})
+
+	t.Run("previous args are restored", func(t *testing.T) {
+		cmd.SetArgs([]string{"--some-val", "previous"})
+		t.Cleanup(func() {
+			cmd.SetArgs(nil)
+		})
+		Run(t, cmd, "--some-val", "other").ExpectSuccess()
+		assert.Equal(t, []string{"--some-val", "previous"}, getArgs(cmd.Command))
+	})
+}
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestResult_failures(t *testing.T) {
+	fake := &fakeT{TB: t}
+	Run(fake, newSomeCommand(), "--some-val", "fail").
+		ExpectSuccess().
+		ExpectErrorContains("other error").
+		ExpectErrorIs(os.ErrNotExist).
+		ExpectLoggedErrorIs(os.ErrNotExist).
+		ExpectStdout("other").
+		ExpectStdoutContains("other").
+		ExpectStderr("other").
+		ExpectStderrContains("other")
+	assert.Equal(t, []string{
+		"expected exit code 0, but got 3",
+		"expected no error, but got: some error",
+		`expected error containing "other error", but got: some error`,
+		"expected error matching file does not exist, but got: some error",
+		"expected logged error matching file does not exist, but got: [some error]",
+		"expected stdout to be\nother\nbut got\ngot fail",
+		"expected stdout to contain\nother\nbut got\ngot fail",
+		"expected stderr to be\nother\nbut got\n",
+		"expected stderr to contain\nother\nbut got\n",
+	}, fake.errors)
+}
@@ -0,0 +1,115 @@
+package commandtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/spf13/cobra"
+)
+
+// UpdateFlagName is the flag given to "go test" to rewrite the golden files, see ExpectHelpGolden.
+// The flag is not registered by this package, as this would conflict with test packages defining it on their own.
+// Define it in the test package with flag.Bool(UpdateFlagName, false, "update golden files").
+const UpdateFlagName = "update"
+
+// UpdateGoldenEnvVar is the environment variable which rewrites the golden files if set to "1",
+// as an alternative to the flag -update, see UpdateFlagName.
+const UpdateGoldenEnvVar = "UPDATE_GOLDEN"
+
+// GoldenOptions are used by ExpectHelpGolden.
+type GoldenOptions struct {
+	// Dir is the directory containing the golden files, defaults to "testdata/help".
+	Dir string
+	// ProgramName is shown as the name of the root command, defaults to "app".
+	ProgramName string
+	// Width is the terminal width, given as COLUMNS environment variable, defaults to 80.
+	// This makes the wrapping of the help output independent of the terminal running the tests.
+	Width int
+	// Update rewrites the golden files instead of comparing them.
+	// Enabled by the flag -update or the environment variable UPDATE_GOLDEN=1, see UpdateFlagName.
+	Update bool
+	// IgnoreUpdate disables Update, even if the flag -update or the environment variable UPDATE_GOLDEN=1 is given.
+	// This is useful for tests expecting differences to the golden files.
+	IgnoreUpdate bool
+}
+
+func (o GoldenOptions) withDefaults() GoldenOptions {
+	if o.Dir == "" {
+		o.Dir = filepath.Join("testdata", "help")
+	}
+	if o.ProgramName == "" {
+		o.ProgramName = "app"
+	}
+	if o.Width == 0 {
+		o.Width = 80
+	}
+	if f := flag.Lookup(UpdateFlagName); f != nil && f.Value.String() == "true" {
+		o.Update = true
+	}
+	if os.Getenv(UpdateGoldenEnvVar) == "1" {
+		o.Update = true
+	}
+	if o.IgnoreUpdate {
+		o.Update = false
+	}
+	return o
+}
+
+// ExpectHelpGolden renders the --help output of the given command and each sub command
+// and compares it with the golden files named after the command path, for example "testdata/help/app-sub.golden".
+// Run "go test" with -update or the environment variable UPDATE_GOLDEN=1 to rewrite the golden files,
+// see GoldenOptions.Update. Sets the environment variable COLUMNS, so the test must not run in parallel.
+// This makes the help output part of the tested API of the application.
+func ExpectHelpGolden(t testing.TB, root command.Command, options GoldenOptions) {
+	t.Helper()
+	options = options.withDefaults()
+	t.Setenv("COLUMNS", strconv.Itoa(options.Width))
+	restoreDisplayName := setDisplayName(root.Command, options.ProgramName)
+	defer restoreDisplayName()
+
+	if options.Update {
+		if err := os.MkdirAll(options.Dir, 0o755); err != nil { //nolint:mnd
+			t.Fatalf("cannot create golden file directory: %v", err)
+		}
+	}
+	for cmd := range root.All() {
+		args := strings.Fields(cmd.CommandPath())[1:]
+		result := Run(t, root, append(args, "--help")...).ExpectSuccess()
+		goldenFile := filepath.Join(options.Dir, strings.ReplaceAll(cmd.CommandPath(), " ", "-")+".golden")
+		if options.Update {
+			if err := os.WriteFile(goldenFile, []byte(result.Stdout), 0o644); err != nil { //nolint:gosec,mnd
+				t.Fatalf("cannot write golden file: %v", err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(goldenFile) //nolint:gosec
+		if err != nil {
+			t.Errorf("cannot read golden file, run with -%s or %s=1 to create it: %v", UpdateFlagName, UpdateGoldenEnvVar, err)
+			continue
+		}
+		if string(expected) != result.Stdout {
+			t.Errorf("help output of '%s' differs from golden file %s, run with -%s or %s=1 to update it:\n%s",
+				cmd.CommandPath(), goldenFile, UpdateFlagName, UpdateGoldenEnvVar, result.Stdout)
+		}
+	}
+}
+
+func setDisplayName(cmd *cobra.Command, displayName string) (restore func()) {
+	previous, found := cmd.Annotations[cobra.CommandDisplayNameAnnotation]
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[cobra.CommandDisplayNameAnnotation] = displayName
+	return func() {
+		if found {
+			cmd.Annotations[cobra.CommandDisplayNameAnnotation] = previous
+		} else {
+			delete(cmd.Annotations, cobra.CommandDisplayNameAnnotation)
+		}
+	}
+}
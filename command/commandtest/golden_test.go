@@ -0,0 +1,96 @@
+package commandtest
+
+import (
+	goflag "flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSomeCommandTree() command.Command {
+	var (
+		verbose bool
+		name    string
+	)
+	return command.New().
+		Use("some-binary-name").
+		Short("Some app").
+		Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Usage: "Be verbose", Persistent: true}).
+		AddCommands(
+			command.New().
+				Use("greet").
+				Short("Greets someone").
+				Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "name", Usage: "Name to greet"}).
+				Run(func() error {
+					return nil
+				}),
+		)
+}
+
+// update is the flag to rewrite the golden files, see ExpectHelpGolden.
+var update = goflag.Bool(UpdateFlagName, false, "update golden files") //nolint:gochecknoglobals
+
+func TestExpectHelpGolden(t *testing.T) {
+	t.Run("matches golden files", func(t *testing.T) {
+		cmd := newSomeCommandTree()
+		ExpectHelpGolden(t, cmd, GoldenOptions{})
+		assert.Equal(t, "some-binary-name", cmd.DisplayName())
+	})
+
+	t.Run("golden files missing", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+		ExpectHelpGolden(fake, newSomeCommandTree(), GoldenOptions{Dir: t.TempDir(), IgnoreUpdate: true})
+		assert.Len(t, fake.errors, 2)
+		assert.Contains(t, fake.errors[0], "cannot read golden file, run with -update or UPDATE_GOLDEN=1 to create it")
+	})
+
+	t.Run("update golden files", func(t *testing.T) {
+		dir := t.TempDir()
+		ExpectHelpGolden(t, newSomeCommandTree(), GoldenOptions{Dir: dir, Update: true})
+		ExpectHelpGolden(t, newSomeCommandTree(), GoldenOptions{Dir: dir})
+		fake := &fakeT{TB: t}
+		ExpectHelpGolden(fake, newSomeCommandTree().Short("Changed"), GoldenOptions{Dir: dir, IgnoreUpdate: true})
+		assert.Len(t, fake.errors, 1)
+	})
+
+	t.Run("update with flag or environment variable", func(t *testing.T) {
+		previous := *update
+		t.Cleanup(func() {
+			*update = previous
+		})
+		*update = true
+		assert.True(t, GoldenOptions{}.withDefaults().Update)
+		assert.False(t, GoldenOptions{IgnoreUpdate: true}.withDefaults().Update)
+		*update = false
+		t.Setenv(UpdateGoldenEnvVar, "1")
+		assert.True(t, GoldenOptions{}.withDefaults().Update)
+		t.Setenv(UpdateGoldenEnvVar, "")
+		assert.False(t, GoldenOptions{}.withDefaults().Update)
+	})
+
+	t.Run("golden files differ", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"app.golden", "app-greet.golden"} {
+			content, err := os.ReadFile(filepath.Join("testdata", "help", name))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600))
+		}
+		fake := &fakeT{TB: t}
+		ExpectHelpGolden(fake, newSomeCommandTree().Short("Changed"), GoldenOptions{Dir: dir, IgnoreUpdate: true})
+		assert.Len(t, fake.errors, 1)
+		assert.Contains(t, fake.errors[0], "help output of 'app' differs from golden file "+filepath.Join(dir, "app.golden"))
+	})
+
+	t.Run("terminal width is normalized", func(t *testing.T) {
+		t.Setenv("COLUMNS", "30")
+		expected, err := os.ReadFile(filepath.Join("testdata", "help", "app-greet.golden"))
+		require.NoError(t, err)
+		assert.NotEqual(t, string(expected), Run(t, newSomeCommandTree(), "greet", "--help").Stdout)
+		ExpectHelpGolden(t, newSomeCommandTree(), GoldenOptions{IgnoreUpdate: true})
+	})
+}
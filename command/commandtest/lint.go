@@ -0,0 +1,16 @@
+package commandtest
+
+import (
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+)
+
+// Lint reports each finding of [command.Command.Lint] for the given root command as a test error.
+// Uses [command.DefaultLintRules] if no rules are given.
+func Lint(t testing.TB, root command.Command, rules ...command.LintRule) {
+	t.Helper()
+	for _, finding := range root.Lint(rules...) {
+		t.Errorf("lint: %s", finding)
+	}
+}
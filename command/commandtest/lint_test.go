@@ -0,0 +1,24 @@
+package commandtest
+
+import (
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("no findings", func(t *testing.T) {
+		Lint(t, newGreetCommand())
+	})
+
+	t.Run("findings are reported", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+		Lint(fake, newGreetCommand(), command.LintFlagUsage())
+		assert.Equal(t, []string{
+			"lint: app greet: flag-usage: flag --name has empty usage",
+			"lint: app greet: flag-usage: flag --name-file has empty usage",
+			"lint: app greet: flag-usage: flag --out has empty usage",
+		}, fake.errors)
+	})
+}
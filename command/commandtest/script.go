@@ -0,0 +1,296 @@
+package commandtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+)
+
+// RunScripts runs each script file matching the given glob pattern as a sub test, see RunScript.
+// The scripts run one after another, and the test must not run in parallel, see RunScript.
+func RunScripts(t *testing.T, pattern string, newCommand func() command.Command) {
+	t.Helper()
+	fileNames, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatalf("invalid script pattern: %v", err)
+	}
+	if len(fileNames) == 0 {
+		t.Fatalf("no scripts found for pattern %s", pattern)
+	}
+	for _, fileName := range fileNames {
+		t.Run(filepath.Base(fileName), func(t *testing.T) {
+			content, err := os.ReadFile(fileName) //nolint:gosec
+			if err != nil {
+				t.Fatalf("cannot read script: %v", err)
+			}
+			RunScript(t, fileName, content, newCommand)
+		})
+	}
+}
+
+// RunScript runs a scenario script in txtar format against commands constructed by newCommand.
+//
+// The comment section of the archive contains the script, one command per line.
+// The files of the archive are extracted into a temporary directory, which is the working directory
+// while running the script and is available as $WORK.
+// Lines starting with # are comments. Arguments are separated by whitespace,
+// can be quoted with single quotes (use two single quotes for a literal one),
+// and unquoted arguments expand environment variables like $WORK.
+// Prefix a command with ! to negate it. The supported commands are:
+//
+//   - exec app args...: Executes a new command with the given arguments in-process, where app is the name of the command.
+//     Fails if the exit code is non-zero, or zero if negated.
+//   - stdout regex, stderr regex: Matches the output of the last exec against the regular expression.
+//   - env KEY=value...: Sets the environment variables until the script ends.
+//   - exists file...: Checks that the files exist.
+//   - cmp file1 file2: Compares the content of the two files.
+//
+// The commands run in-process, so the script does not run in an isolated environment:
+// The working directory of the process is changed and the environment variables are set
+// for the whole process until the script ends. Therefore, RunScript must not be called in parallel tests,
+// see [testing.T.Parallel], and panics if called in a parallel test, see [testing.T.Setenv].
+func RunScript(t testing.TB, name string, script []byte, newCommand func() command.Command) {
+	t.Helper()
+	comment, files := parseTxtar(script)
+	workDir := t.TempDir()
+	for _, file := range files {
+		fileName := filepath.Join(workDir, file.name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil { //nolint:mnd
+			t.Fatalf("cannot create directory for %s: %v", file.name, err)
+		}
+		if err := os.WriteFile(fileName, file.data, 0o644); err != nil { //nolint:gosec,mnd
+			t.Fatalf("cannot write %s: %v", file.name, err)
+		}
+	}
+	previousDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("cannot get working directory: %v", err)
+	}
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatalf("cannot change working directory: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(previousDir)
+	}()
+	t.Setenv("WORK", workDir)
+
+	s := scriptState{t: t, newCommand: newCommand}
+	for i, line := range strings.Split(string(comment), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := s.run(line); err != nil {
+			t.Fatalf("%s:%d: %s: %v", name, i+1, line, err)
+		}
+	}
+}
+
+var (
+	errScriptSyntax  = errors.New("invalid syntax")
+	errScriptFailure = errors.New("failure")
+)
+
+type scriptState struct {
+	t          testing.TB
+	newCommand func() command.Command
+	stdout     string
+	stderr     string
+}
+
+func (s *scriptState) run(line string) error {
+	args, err := splitScriptLine(line)
+	if err != nil {
+		return err
+	}
+	negate := false
+	if args[0] == "!" {
+		negate = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing command", errScriptSyntax)
+	}
+	switch name, args := args[0], args[1:]; name {
+	case "exec":
+		return s.exec(negate, args)
+	case "stdout":
+		return matchOutput("stdout", s.stdout, negate, args)
+	case "stderr":
+		return matchOutput("stderr", s.stderr, negate, args)
+	case "env":
+		return s.env(negate, args)
+	case "exists":
+		return exists(negate, args)
+	case "cmp":
+		return compareFiles(negate, args)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errScriptSyntax, name)
+	}
+}
+
+func (s *scriptState) exec(negate bool, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: usage: exec app args...", errScriptSyntax)
+	}
+	cmd := s.newCommand()
+	if args[0] != cmd.Name() {
+		return fmt.Errorf("%w: unknown program %q, expected %q", errScriptSyntax, args[0], cmd.Name())
+	}
+	result := Run(s.t, cmd, args[1:]...)
+	s.stdout, s.stderr = result.Stdout, result.Stderr
+	switch {
+	case negate && result.ExitCode == 0:
+		return fmt.Errorf("%w: unexpected success", errScriptFailure)
+	case !negate && result.ExitCode != 0:
+		return fmt.Errorf("%w: exit code %d: %v\n%s", errScriptFailure, result.ExitCode, result.Err, result.Stderr)
+	}
+	return nil
+}
+
+func matchOutput(name, output string, negate bool, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: %s regex", errScriptSyntax, name)
+	}
+	regex, err := regexp.Compile("(?m)" + args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %w", errScriptSyntax, err)
+	}
+	if matched := regex.MatchString(output); matched == negate {
+		if negate {
+			return fmt.Errorf("%w: unexpected match for %q in %s:\n%s", errScriptFailure, args[0], name, output)
+		}
+		return fmt.Errorf("%w: no match for %q in %s:\n%s", errScriptFailure, args[0], name, output)
+	}
+	return nil
+}
+
+func (s *scriptState) env(negate bool, args []string) error {
+	if negate {
+		return fmt.Errorf("%w: env cannot be negated", errScriptSyntax)
+	}
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("%w: usage: env KEY=value...", errScriptSyntax)
+		}
+		s.t.Setenv(key, value)
+	}
+	return nil
+}
+
+func exists(negate bool, args []string) error {
+	for _, fileName := range args {
+		_, err := os.Stat(fileName)
+		switch {
+		case negate && err == nil:
+			return fmt.Errorf("%w: %s unexpectedly exists", errScriptFailure, fileName)
+		case !negate && err != nil:
+			return fmt.Errorf("%w: %w", errScriptFailure, err)
+		}
+	}
+	return nil
+}
+
+func compareFiles(negate bool, args []string) error {
+	if len(args) != 2 { //nolint:mnd
+		return fmt.Errorf("%w: usage: cmp file1 file2", errScriptSyntax)
+	}
+	content1, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %w", errScriptFailure, err)
+	}
+	content2, err := os.ReadFile(args[1])
+	if err != nil {
+		return fmt.Errorf("%w: %w", errScriptFailure, err)
+	}
+	if equal := bytes.Equal(content1, content2); equal == negate {
+		return fmt.Errorf("%w: %s and %s unexpectedly (not) equal:\n%s\n---\n%s",
+			errScriptFailure, args[0], args[1], content1, content2)
+	}
+	return nil
+}
+
+// splitScriptLine splits the line into arguments, see RunScript.
+func splitScriptLine(line string) (args []string, err error) {
+	var (
+		current  strings.Builder
+		inWord   bool
+		inQuotes bool
+	)
+	for i := 0; i < len(line); i++ {
+		switch char := line[i]; {
+		case inQuotes && char == '\'':
+			if i+1 < len(line) && line[i+1] == '\'' {
+				current.WriteByte('\'')
+				i++
+			} else {
+				inQuotes = false
+			}
+		case inQuotes:
+			current.WriteByte(char)
+		case char == '\'':
+			inQuotes, inWord = true, true
+		case char == ' ' || char == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			// find end of unquoted part to expand variables
+			end := strings.IndexAny(line[i:], " \t'")
+			if end < 0 {
+				end = len(line) - i
+			}
+			current.WriteString(os.ExpandEnv(line[i : i+end]))
+			inWord = true
+			i += end - 1
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("%w: unterminated quote", errScriptSyntax)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// txtarFile is a file of a txtar archive, see parseTxtar.
+type txtarFile struct {
+	name string
+	data []byte
+}
+
+// parseTxtar parses the txtar archive format, see https://pkg.go.dev/golang.org/x/tools/txtar.
+// Files are started by marker lines "-- name --".
+func parseTxtar(data []byte) (comment []byte, files []txtarFile) {
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if name, ok := txtarMarker(trimmed); ok {
+			files = append(files, txtarFile{name: name})
+			continue
+		}
+		if len(files) == 0 {
+			comment = append(comment, line...)
+		} else {
+			files[len(files)-1].data = append(files[len(files)-1].data, line...)
+		}
+	}
+	return
+}
+
+func txtarMarker(line []byte) (name string, ok bool) {
+	if !bytes.HasPrefix(line, []byte("-- ")) || !bytes.HasSuffix(line, []byte(" --")) || len(line) < len("-- x --") {
+		return "", false
+	}
+	return strings.TrimSpace(string(line[3 : len(line)-3])), true
+}
@@ -0,0 +1,108 @@
+package commandtest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGreetCommand() command.Command {
+	var (
+		name     = os.Getenv("GREET_NAME")
+		nameFile string
+		out      string
+	)
+	greet := command.New().Use("greet")
+	return command.New().
+		Use("app").
+		AddCommands(greet.
+			Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "name"}).
+			Flag(flag.String(&nameFile, flag.NotEmpty), flag.RegisterOptions{Name: "name-file"}).
+			Flag(flag.String(&out, flag.NotEmpty), flag.RegisterOptions{Name: "out"}).
+			Run(func() error {
+				if nameFile != "" {
+					content, err := os.ReadFile(nameFile)
+					if err != nil {
+						return err
+					}
+					name = strings.TrimSpace(string(content))
+				}
+				greeting := "Hello " + name + "!\n"
+				if out != "" {
+					return os.WriteFile(out, []byte(greeting), 0o600)
+				}
+				greet.Print(greeting)
+				return nil
+			}),
+		)
+}
+
+func TestRunScripts(t *testing.T) {
+	RunScripts(t, "testdata/scripts/*.txtar", newGreetCommand)
+}
+
+func TestRunScript_failures(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{"unknown command", "foo", `test.txtar:1: foo: invalid syntax: unknown command "foo"`},
+		{"unknown program", "exec other", `test.txtar:1: exec other: invalid syntax: unknown program "other", expected "app"`},
+		{"exec fails", "\nexec app greet --unknown", "test.txtar:2: exec app greet --unknown: failure: exit code 1: unknown flag: --unknown\nError: unknown flag: --unknown\n"},
+		{"exec succeeds unexpectedly", "! exec app greet", "test.txtar:1: ! exec app greet: failure: unexpected success"},
+		{"stdout does not match", "exec app greet --name x\nstdout y", `test.txtar:2: stdout y: failure: no match for "y" in stdout:` + "\nHello x!\n"},
+		{"stderr matches", "exec app greet --name x\n! stderr ^$", `test.txtar:2: ! stderr ^$: failure: unexpected match for "^$" in stderr:` + "\n"},
+		{"unterminated quote", "stdout 'x", "test.txtar:1: stdout 'x: invalid syntax: unterminated quote"},
+		{"file missing", "exists foo", "test.txtar:1: exists foo: failure: stat foo: no such file or directory"},
+		{"files differ", "cmp a b\n-- a --\na\n-- b --\nb\n", "test.txtar:1: cmp a b: failure: a and b unexpectedly (not) equal:\na\n\n---\nb\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeFatalT{TB: t}
+			func() {
+				defer func() {
+					_ = recover()
+				}()
+				RunScript(fake, "test.txtar", []byte(tt.script), newGreetCommand)
+			}()
+			assert.Equal(t, tt.want, fake.fatal)
+		})
+	}
+}
+
+// fakeFatalT records the fatal message and panics to stop execution.
+type fakeFatalT struct {
+	testing.TB
+
+	fatal string
+}
+
+func (f *fakeFatalT) Helper() {}
+
+func (f *fakeFatalT) Fatalf(format string, args ...any) {
+	f.fatal = fmt.Sprintf(format, args...)
+	panic(f.fatal)
+}
+
+func Test_splitScriptLine(t *testing.T) {
+	t.Setenv("SOME_VAR", "some value")
+	args, err := splitScriptLine(`exec  app 'quoted  ''arg''' $SOME_VAR 'not $SOME_VAR' prefix-$SOME_VAR`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"exec", "app", "quoted  'arg'", "some value", "not $SOME_VAR", "prefix-some value"}, args)
+}
+
+func Test_parseTxtar(t *testing.T) {
+	comment, files := parseTxtar([]byte("comment\n-- a.txt --\ncontent a\n-- dir/b.txt --\ncontent b\n"))
+	assert.Equal(t, "comment\n", string(comment))
+	assert.Equal(t, []txtarFile{
+		{"a.txt", []byte("content a\n")},
+		{"dir/b.txt", []byte("content b\n")},
+	}, files)
+}
@@ -0,0 +1,14 @@
+package command
+
+import "github.com/spf13/cobra"
+
+// CompletionDirective tells the shell how to handle the completion candidates, see CaptureCompletions.
+// It is the same type as [cobra.ShellCompDirective].
+type CompletionDirective = cobra.ShellCompDirective
+
+// The hidden completion command of Cobra and its error directive, used by CaptureCompletions,
+// as testing.go must not import Cobra.
+const (
+	completionRequestCmd     = cobra.ShellCompRequestCmd
+	completionDirectiveError = cobra.ShellCompDirectiveError
+)
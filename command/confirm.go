@@ -0,0 +1,97 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ErrNotConfirmed is returned by Execute if the confirmation required by RequireConfirmation is missing or declined.
+var ErrNotConfirmed = errors.New("not confirmed")
+
+// confirmFlagName is the flag registered by RequireConfirmation.
+const confirmFlagName = "yes"
+
+// RequireConfirmation asks for confirmation with the given message before running this command,
+// such as "Delete {{.name}}?". The message is a [text/template] executed with the values of the flags by name,
+// use for example {{index . "dry-run"}} for names with dashes.
+// Flags registered with [flag.RegisterOptions.Secret] are left out, so referencing them fails the execution.
+// Registers the flag --yes (-y) to confirm without asking. The flag is registered only once per command,
+// so calling RequireConfirmation again adds another confirmation using the same flag.
+// A --yes flag registered before is used as is, and the shorthand is omitted if -y is used already.
+//
+// Asking requires prompting, see WithPrompting and WithPrompter.
+// The confirmation is asked after the flags have been bound and validated, just before Run.
+// A declined confirmation, or a missing --yes without prompting, fails the execution with ErrNotConfirmed,
+// which Execute reports without the usage and exits with exit code 1.
+// Panics if the message is not a valid template.
+func (c Command) RequireConfirmation(message string) Command {
+	messageTemplate := template.Must(template.New("confirmation").Option("missingkey=error").Parse(message))
+	if c.Flags().Lookup(confirmFlagName) == nil && c.PersistentFlags().Lookup(confirmFlagName) == nil {
+		options := flag.RegisterOptions{Name: confirmFlagName, Shorthand: "y", Usage: "Confirm without asking"}
+		if c.Flags().ShorthandLookup(options.Shorthand) != nil || c.PersistentFlags().ShorthandLookup(options.Shorthand) != nil {
+			options.Shorthand = ""
+		}
+		c = c.Flag(flag.Bool(new(bool)), options)
+	}
+	*c.confirmations = append(*c.confirmations, func(cmd *cobra.Command, prompter *Prompter) error {
+		if yes := cmd.Flags().Lookup(confirmFlagName); yes != nil && yes.Value.String() == "true" {
+			return nil
+		}
+		if prompter == nil {
+			return fmt.Errorf("%w: use --%s to confirm", ErrNotConfirmed, confirmFlagName)
+		}
+		values := map[string]string{}
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !isSecret(f) {
+				values[f.Name] = f.Value.String()
+			}
+		})
+		var builder strings.Builder
+		if err := messageTemplate.Execute(&builder, values); err != nil {
+			return fmt.Errorf("cannot render confirmation message: %w", err)
+		}
+		confirmed, err := prompter.confirm(builder.String())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrNotConfirmed, err)
+		}
+		if !confirmed {
+			return ErrNotConfirmed
+		}
+		return nil
+	})
+	return c
+}
+
+// installConfirmation asks for the confirmations added by RequireConfirmation after the validation,
+// see installValidation. The returned function restores the previous state.
+func (c Command) installConfirmation(prompter *Prompter) (restore func()) {
+	var restores []func()
+	for command := range c.All() {
+		if len(*command.confirmations) == 0 {
+			continue
+		}
+		previous := command.PreRunE
+		command.PreRunE = chainCobraRun(previous, func(cmd *cobra.Command, _ []string) error {
+			for _, confirmation := range *command.confirmations {
+				if err := confirmation(cmd, prompter); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		restores = append(restores, func() {
+			command.PreRunE = previous
+		})
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_RequireConfirmation(t *testing.T) {
+	var (
+		name   string
+		gotRun bool
+	)
+	cmd := New().Use("delete").
+		Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "name", Required: true}).
+		Validate(func() error {
+			if name == "invalid" {
+				return errors.New("invalid name")
+			}
+			return nil
+		}).
+		RequireConfirmation("Delete {{.name}}?").
+		Run(func() error {
+			gotRun = true
+			return nil
+		})
+	cmd.CaptureCobraOutput(t) // avoid confusing test output
+
+	execute := func(t *testing.T, input string, exitCode int, args ...string) (string, error) {
+		t.Helper()
+		gotRun = false
+		var out strings.Builder
+		err := cmd.Execute(WithArgs(args...), WithReset(), AssertExitCode(t, exitCode), WithErrorLogger(func(error) {}),
+			WithPrompter(NewPrompter(strings.NewReader(input), &out)))
+		return out.String(), err
+	}
+
+	t.Run("confirmed", func(t *testing.T) {
+		out, err := execute(t, "y\n", 0, "--name", "some")
+		require.NoError(t, err)
+		assert.Equal(t, "Delete some? [y/N]: ", out)
+		assert.True(t, gotRun)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		getStdout, getStderr := cmd.CaptureCobraOutput(t)
+		_, err := execute(t, "\n", 1, "--name", "some")
+		require.ErrorIs(t, err, ErrNotConfirmed)
+		assert.Equal(t, "Error: not confirmed\n", getStderr())
+		assert.Empty(t, getStdout())
+		assert.False(t, gotRun)
+	})
+
+	t.Run("confirmed with flag", func(t *testing.T) {
+		out, err := execute(t, "", 0, "--name", "some", "-y")
+		require.NoError(t, err)
+		assert.Empty(t, out)
+		assert.True(t, gotRun)
+	})
+
+	t.Run("invalid flags are reported before asking", func(t *testing.T) {
+		out, err := execute(t, "y\n", 1, "--name", "invalid")
+		require.ErrorContains(t, err, "invalid name")
+		assert.Empty(t, out)
+	})
+
+	t.Run("without prompting is not confirmed", func(t *testing.T) {
+		getStdout, getStderr := cmd.CaptureCobraOutput(t)
+		err := cmd.Execute(WithArgs("--name", "some"), WithReset(), AssertExitCode(t, 1))
+		require.ErrorIs(t, err, ErrNotConfirmed)
+		assert.Equal(t, "Error: not confirmed: use --yes to confirm\n", getStderr())
+		assert.Empty(t, getStdout())
+		assert.False(t, gotRun)
+	})
+
+	t.Run("invalid template panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			New().RequireConfirmation("{{")
+		})
+	})
+}
+
+func TestCommand_RequireConfirmation_flags(t *testing.T) {
+	t.Run("twice", func(t *testing.T) {
+		cmd := New().RequireConfirmation("First?").RequireConfirmation("Second?").Run(func() error { return nil })
+		var out strings.Builder
+		require.NoError(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 0),
+			WithPrompter(NewPrompter(strings.NewReader("y\ny\n"), &out))))
+		assert.Equal(t, "First? [y/N]: Second? [y/N]: ", out.String())
+		require.NoError(t, cmd.Execute(WithArgs("-y"), WithReset(), AssertExitCode(t, 0)))
+	})
+
+	t.Run("shorthand in use", func(t *testing.T) {
+		var yaml bool
+		cmd := New().
+			Flag(flag.Bool(&yaml), flag.RegisterOptions{Name: "yaml", Shorthand: "y"}).
+			RequireConfirmation("Sure?").
+			Run(func() error { return nil })
+		require.NoError(t, cmd.Execute(WithArgs("--yes"), WithReset(), AssertExitCode(t, 0)))
+		assert.Empty(t, cmd.Flags().Lookup("yes").Shorthand)
+	})
+
+	t.Run("secrets are left out", func(t *testing.T) {
+		var password string
+		cmd := New().
+			Flag(flag.String(&password, flag.NotEmpty), flag.RegisterOptions{Name: "password", Secret: true}).
+			RequireConfirmation("Use {{.password}}?").
+			Run(func() error { return nil })
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		var out strings.Builder
+		require.ErrorContains(t, cmd.Execute(WithArgs("--password", "secret"), WithReset(), AssertExitCode(t, 1),
+			WithPrompter(NewPrompter(strings.NewReader("y\n"), &out))),
+			`cannot render confirmation message`)
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// WithDeprecationWarnings uses the given sink for the warnings about used deprecated flag aliases,
+// see [flag.Alias.Deprecated]. Each warning is reported once per execution.
+// By default, prints the warnings to the error output of the command, see [cobra.Command.PrintErrln].
+func WithDeprecationWarnings(sink func(warning string)) ExecuteOption {
+	return applyToExecuteOptions(func(options *executeOptions) {
+		options.DeprecationWarnings = sink
+	})
+}
+
+// installDeprecationWarnings reports the used deprecated aliases to the given sink for this command and all sub commands.
+// Reporting happens after the flags have been parsed, so each warning is reported once, even if the alias is repeated.
+// The returned function restores the previous state.
+func (c Command) installDeprecationWarnings(sink func(warning string)) (restore func()) {
+	var restores []func()
+	for command := range c.All() {
+		previous := command.PreRunE
+		command.PreRunE = chainCobraRun(func(cmd *cobra.Command, _ []string) error {
+			cmd.Flags().VisitAll(func(f *pflag.Flag) {
+				if aliasValue, ok := flag.ValueAs[flag.AliasValue](f.Value); ok && aliasValue.Alias.Deprecated && f.Changed {
+					sink(aliasValue.DeprecationWarning())
+				}
+			})
+			return nil
+		}, previous)
+		restores = append(restores, func() {
+			command.PreRunE = previous
+		})
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_FlagAliases(t *testing.T) {
+	var (
+		output string
+		quiet  bool
+		other  string
+	)
+	cmd := New().Use("app").
+		Flag(flag.String(&output, flag.NotEmpty), flag.RegisterOptions{Name: "output", Usage: "Output file", Required: true}.
+			Apply(flag.WithAliases("out"), flag.RenamedFrom("output-file"))).
+		Flag(flag.Bool(&quiet), flag.RegisterOptions{Name: "quiet", Usage: "Be quiet"}.Apply(flag.RenamedFrom("silent"))).
+		Flag(flag.String(&other, flag.NotEmpty), flag.RegisterOptions{Name: "other", Usage: "Something else"}).
+		MarkFlagsMutuallyExclusive(&output, &other).
+		Run(func() error { return nil })
+
+	var warnings []string
+	execute := func(args ...string) error {
+		warnings = nil
+		return cmd.Execute(WithArgs(args...), WithReset(), WithExiter(func(int) {}),
+			WithDeprecationWarnings(func(warning string) {
+				warnings = append(warnings, warning)
+			}))
+	}
+
+	t.Run("aliases set target", func(t *testing.T) {
+		require.NoError(t, execute("--out", "a"))
+		assert.Equal(t, "a", output)
+		assert.Empty(t, warnings)
+		assert.True(t, cmd.Flags().Lookup("output").Changed)
+	})
+
+	t.Run("deprecated alias warns once", func(t *testing.T) {
+		require.NoError(t, execute("--output-file", "a", "--output-file", "b", "--silent"))
+		assert.Equal(t, "b", output)
+		assert.True(t, quiet)
+		assert.Equal(t, []string{
+			"Flag --output-file has been deprecated, use --output instead",
+			"Flag --silent has been deprecated, use --quiet instead",
+		}, warnings)
+	})
+
+	t.Run("constraints treat alias as same flag", func(t *testing.T) {
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, execute("--output-file", "a", "--other", "b"),
+			"if any flags in the group [output other] are set none of the others can be; [other output] were all set")
+		require.ErrorContains(t, execute("--silent"), `required flag(s) "output" not set`)
+	})
+
+	t.Run("deprecated alias warns with aggregated errors", func(t *testing.T) {
+		warnings = nil
+		require.NoError(t, cmd.Execute(WithArgs("--output-file", "a"), WithReset(), WithAggregatedErrors(),
+			AssertExitCode(t, 0), WithDeprecationWarnings(func(warning string) {
+				warnings = append(warnings, warning)
+			})))
+		assert.Equal(t, "a", output)
+		assert.Equal(t, []string{"Flag --output-file has been deprecated, use --output instead"}, warnings)
+	})
+
+	t.Run("default warnings", func(t *testing.T) {
+		_, getStderr := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--out", "a", "--silent"), WithReset(), AssertExitCode(t, 0)))
+		assert.Equal(t, "Flag --silent has been deprecated, use --quiet instead\n", getStderr())
+	})
+
+	t.Run("help output", func(t *testing.T) {
+		getStdout, _ := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--help"), WithReset(), AssertExitCode(t, 0)))
+		assert.Equal(t, `Usage:
+  app [flags]
+
+Flags:
+  -h, --help            help for app
+      --other string    Something else
+      --out string      Alias for --output
+      --output string   Output file
+      --quiet[=true]    Be quiet
+`, getStdout())
+	})
+}
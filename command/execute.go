@@ -10,34 +10,85 @@ import (
 // By default, exits the application with proper exit code and never returns.
 // By default, logs an error originating from Run callback execution using [log.Printf].
 // See  WithExiter and WithErrorLogger to change this default behavior (which can be useful for testing).
+// See WithAggregatedErrors to report all flag errors at once.
+// See WithReset to execute the same command several times.
+// Runs the persistent hooks of all parents of the executed command, see AddPersistentPreRun.
+// See WithPrompting to ask for missing required flags interactively.
+// See WithDeprecationWarnings to change where warnings about deprecated flag aliases are reported.
+// Reports ErrNotConfirmed without the usage, see RequireConfirmation.
+// Wraps the flag usages of the help output to the terminal width given by the environment variable COLUMNS, if set.
 //
 //nolint:wrapcheck
 func (c Command) Execute(options ...ExecuteOption) (err error) {
-	// Note: That potentially modifies the state of the internal cobra.Command
-	// So for testing, ensure the previous state is restored if necessary.
-	for _, option := range options {
-		option.applyToCommand(c)
-	}
-
-	err = c.Command.Execute()
-
 	opts := executeOptions{
 		Exiter: os.Exit,
 		ErrorLogger: func(err error) {
 			log.Printf("Command failed: %s", err.Error())
 		},
+		DeprecationWarnings: func(warning string) {
+			c.PrintErrln(warning)
+		},
 	}.apply(options)
 
+	// reset before applying the options to the command, as for example WithArgs sets the arguments
+	if opts.Reset {
+		c.Reset()
+	} else {
+		for command := range c.All() {
+			if command.resetOnExecute {
+				command.resetFlags()
+			}
+		}
+	}
+	// Note: That potentially modifies the state of the internal cobra.Command
+	// So for testing, ensure the previous state is restored if necessary, or use WithReset.
+	for _, option := range options {
+		option.applyToCommand(c)
+	}
+
+	c.applyFlagConstraints()
+	if helpWidth() > 0 {
+		for command := range c.All() {
+			command.enableFlagSections()
+		}
+	}
+	restoreHookChain := c.installHookChain()
+
+	var collector *errorCollector
+	restoreErrorCollector := func() {}
+	if opts.AggregateErrors {
+		collector = &errorCollector{}
+		restoreErrorCollector = c.installErrorCollector(collector)
+	}
+	restorePrompting := c.installPrompting(opts.Prompter)
+	restoreValidation := c.installValidation(collector)
+	restoreConfirmation := c.installConfirmation(opts.Prompter)
+	restoreDeprecationWarnings := c.installDeprecationWarnings(opts.DeprecationWarnings)
+	err = c.Command.Execute()
+	restoreDeprecationWarnings()
+	restoreConfirmation()
+	restoreValidation()
+	restorePrompting()
+	restoreHookChain()
+	restoreErrorCollector()
+	if collector != nil {
+		err = collector.join(err)
+	}
+
 	if err != nil {
 		exitCode := 1
 		var errFromRunCallback fromRunCallbackError
-		if errors.As(err, &errFromRunCallback) {
+		switch {
+		case errors.Is(err, ErrNotConfirmed):
+			// neither a usage error nor a failed run, see RequireConfirmation
+			c.PrintErrln(c.ErrPrefix(), err.Error())
+		case errors.As(err, &errFromRunCallback):
 			var errWithExitCode WithExitCodeError
 			if errors.As(err, &errWithExitCode) {
 				exitCode = errWithExitCode.ExitCode
 			}
 			opts.ErrorLogger(errFromRunCallback.Wrapped)
-		} else {
+		default:
 			// see cobra.Execute implementation, this mimics the behavior as if
 			// SilenceErrors and SilenceUsage were false.
 			c.PrintErrln(c.ErrPrefix(), err.Error())
@@ -110,11 +161,25 @@ func WithErrorLogger(logger func(err error)) ExecuteOption {
 	})
 }
 
+// WithAggregatedErrors makes Command.Execute report all invalid flag values, failed flag bindings,
+// missing required flags and failed validations (see Command.Validate) together as one error,
+// instead of stopping at the first one.
+// The returned error contains one line per problem, mentioning the flag and the source of its value.
+func WithAggregatedErrors() ExecuteOption {
+	return applyToExecuteOptions(func(options *executeOptions) {
+		options.AggregateErrors = true
+	})
+}
+
 // executeOptions are options for running Command.Execute.
-// See WithExiter, WithErrorLogger.
+// See WithExiter, WithErrorLogger, WithAggregatedErrors, WithReset, WithDeprecationWarnings, WithPrompter.
 type executeOptions struct {
-	Exiter      func(exitCode int)
-	ErrorLogger func(err error)
+	Exiter              func(exitCode int)
+	ErrorLogger         func(err error)
+	DeprecationWarnings func(warning string)
+	Prompter            *Prompter
+	AggregateErrors     bool
+	Reset               bool
 }
 
 func (o executeOptions) apply(opts []ExecuteOption) executeOptions {
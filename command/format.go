@@ -0,0 +1,418 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FormatJSON is the Formatter "json" and renders the result as indented JSON.
+func FormatJSON(w io.Writer, result any, _ FormatOptions) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(result) //nolint:wrapcheck
+}
+
+// FormatYAML is the Formatter "yaml" and renders the result as YAML.
+// The result is converted with [encoding/json] first, so the field names and the order of the fields
+// are the same as for FormatJSON.
+func FormatYAML(w io.Writer, result any, _ FormatOptions) error {
+	data, err := toJSONData(result)
+	if err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2) //nolint:mnd
+	if err := encoder.Encode(yamlNode(data)); err != nil {
+		return err //nolint:wrapcheck
+	}
+	return encoder.Close() //nolint:wrapcheck
+}
+
+// yamlNode converts data of toJSONData to a YAML node, keeping the order of the members of objects.
+// Scalars have explicit tags, so strings looking like numbers, such as "0x1F", are quoted.
+func yamlNode(data any) *yaml.Node {
+	switch data := data.(type) {
+	case jsonObject:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, member := range data {
+			node.Content = append(node.Content, yamlNode(member.Key), yamlNode(member.Value))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, element := range data {
+			node.Content = append(node.Content, yamlNode(element))
+		}
+		return node
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: data}
+	case json.Number:
+		if _, err := data.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: data.String()}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: data.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(data)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// FormatTemplate is the Formatter "template" and executes the [text/template] given as FormatOptions.Arg
+// with the result, such as "--output template={{.Name}}".
+func FormatTemplate(w io.Writer, result any, options FormatOptions) error {
+	if options.Arg == "" {
+		return errors.New("template required, use for example template={{.}}")
+	}
+	tmpl, err := template.New("output").Parse(options.Arg)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	return tmpl.Execute(w, result) //nolint:wrapcheck
+}
+
+// FormatJSONPath is the Formatter "jsonpath" and renders the fields selected by the expression
+// given as FormatOptions.Arg, such as "--output jsonpath={.items[*].name}".
+// The result is converted with [encoding/json] first, so the expression uses the JSON field names.
+// Expressions in curly braces may be surrounded by text. An expression is a path consisting of
+// fields ".name", indices "[0]" (negative counting from the end) and wildcards ".*" and "[*]".
+// Selected values are separated by spaces, strings are written as is and other values as JSON.
+func FormatJSONPath(w io.Writer, result any, options FormatOptions) error {
+	if options.Arg == "" {
+		return errors.New("expression required, use for example jsonpath={.name}")
+	}
+	data, err := toJSONData(result)
+	if err != nil {
+		return err
+	}
+	remaining := options.Arg
+	if !strings.Contains(remaining, "{") {
+		remaining = "{" + remaining + "}"
+	}
+	var output strings.Builder
+	for remaining != "" {
+		start := strings.Index(remaining, "{")
+		if start < 0 {
+			output.WriteString(remaining)
+			break
+		}
+		end := strings.Index(remaining[start:], "}")
+		if end < 0 {
+			return fmt.Errorf("unclosed expression in '%s'", options.Arg)
+		}
+		output.WriteString(remaining[:start])
+		selected, err := selectJSONPath(data, remaining[start+1:start+end])
+		if err != nil {
+			return err
+		}
+		for i, value := range selected {
+			if i > 0 {
+				output.WriteString(" ")
+			}
+			output.WriteString(jsonPathString(value))
+		}
+		remaining = remaining[start+end+1:]
+	}
+	_, err = io.WriteString(w, output.String()+"\n")
+	return err //nolint:wrapcheck
+}
+
+// FormatTable is the Formatter "table" and renders the result as table with aligned columns.
+// A slice or array result renders one row per element, any other result renders a single row.
+// For structs, each exported field is a column and the header is the field name in upper case,
+// unless the field has a struct tag `table:"HEADER"`. Fields with the tag `table:"-"` are skipped.
+// Other values are rendered in a single column VALUE.
+// The headers are omitted if FormatOptions.NoHeaders is set.
+func FormatTable(w io.Writer, result any, options FormatOptions) error {
+	rows := reflect.ValueOf(result)
+	for rows.Kind() == reflect.Pointer && !rows.IsNil() {
+		rows = rows.Elem()
+	}
+	switch rows.Kind() {
+	case reflect.Invalid:
+		rows = reflect.ValueOf([]any{})
+	case reflect.Slice, reflect.Array:
+	default:
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(rows.Type()), 0, 1), rows)
+	}
+	elemType := rows.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	var (
+		headers  []string
+		fields   [][]int
+		isStruct = elemType.Kind() == reflect.Struct
+	)
+	if isStruct {
+		for _, field := range reflect.VisibleFields(elemType) {
+			header, hasTag := field.Tag.Lookup("table")
+			if !field.IsExported() || field.Anonymous || header == "-" {
+				continue
+			}
+			if !hasTag || header == "" {
+				header = strings.ToUpper(field.Name)
+			}
+			headers = append(headers, header)
+			fields = append(fields, field.Index)
+		}
+	} else {
+		headers = []string{"VALUE"}
+	}
+	writer := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	if !options.NoHeaders {
+		_, _ = fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	}
+	for i := range rows.Len() {
+		row := rows.Index(i)
+		for row.Kind() == reflect.Pointer || row.Kind() == reflect.Interface {
+			if row.IsNil() {
+				break
+			}
+			row = row.Elem()
+		}
+		cells := make([]string, len(headers))
+		if !isStruct {
+			cells[0] = tableCell(row)
+		} else if row.Kind() == reflect.Struct {
+			for j, index := range fields {
+				if field, err := row.FieldByIndexErr(index); err == nil {
+					cells[j] = tableCell(field)
+				}
+			}
+		}
+		_, _ = fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush() //nolint:wrapcheck
+}
+
+// tableCell renders a value of FormatTable.
+// Slices are joined with commas and nil values are empty.
+func tableCell(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map:
+		if value.IsNil() {
+			return ""
+		}
+	case reflect.Slice:
+		if _, ok := value.Interface().(fmt.Stringer); ok || value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		elements := make([]string, value.Len())
+		for i := range value.Len() {
+			elements[i] = tableCell(value.Index(i))
+		}
+		return strings.Join(elements, ",")
+	default:
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// jsonObject is a JSON object keeping the order of its members, see toJSONData.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	Key   string
+	Value any
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, _ := json.Marshal(member.Key)
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// toJSONData converts the given value with [encoding/json] to
+// a jsonObject, []any, string, [json.Number], bool or nil.
+func toJSONData(value any) (any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decodeJSONData(decoder)
+}
+
+func decodeJSONData(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	switch token {
+	case json.Delim('{'):
+		object := jsonObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err //nolint:wrapcheck
+			}
+			value, err := decodeJSONData(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, jsonMember{fmt.Sprint(key), value})
+		}
+		_, err = decoder.Token()
+		return object, err //nolint:wrapcheck
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeJSONData(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err //nolint:wrapcheck
+	default:
+		return token, nil
+	}
+}
+
+// selectJSONPath returns the values of the given data selected by the path, see FormatJSONPath.
+func selectJSONPath(data any, path string) ([]any, error) {
+	selected := []any{data}
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	for path != "" {
+		var (
+			next []any
+			err  error
+		)
+		switch {
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed index in path '%s'", path)
+			}
+			next, err = selectJSONPathIndex(selected, path[1:end])
+			path = path[end+1:]
+		case strings.HasPrefix(path, "."):
+			end := strings.IndexAny(path[1:], ".[")
+			if end < 0 {
+				end = len(path) - 1
+			}
+			next, err = selectJSONPathField(selected, path[1:end+1])
+			path = path[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path '%s', must start with '.' or '['", path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		selected = next
+	}
+	return selected, nil
+}
+
+func selectJSONPathField(selected []any, name string) (next []any, err error) {
+	for _, value := range selected {
+		object, ok := value.(jsonObject)
+		switch {
+		case name == "":
+			next = append(next, value)
+		case name == "*" && ok:
+			for _, member := range object {
+				next = append(next, member.Value)
+			}
+		case name == "*":
+			next = append(next, jsonPathElements(value)...)
+		case !ok:
+			return nil, fmt.Errorf("cannot select field '%s' of non-object", name)
+		default:
+			found := false
+			for _, member := range object {
+				if member.Key == name {
+					next, found = append(next, member.Value), true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("field '%s' not found", name)
+			}
+		}
+	}
+	return
+}
+
+func selectJSONPathIndex(selected []any, index string) (next []any, err error) {
+	for _, value := range selected {
+		if index == "*" {
+			next = append(next, jsonPathElements(value)...)
+			continue
+		}
+		array, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot select index '%s' of non-array", index)
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index '%s'", index)
+		}
+		if i < 0 {
+			i += len(array)
+		}
+		if i < 0 || i >= len(array) {
+			return nil, fmt.Errorf("index '%s' out of range", index)
+		}
+		next = append(next, array[i])
+	}
+	return
+}
+
+// jsonPathElements returns the elements of an array or the values of an object.
+func jsonPathElements(value any) []any {
+	switch value := value.(type) {
+	case []any:
+		return value
+	case jsonObject:
+		values := make([]any, len(value))
+		for i, member := range value {
+			values[i] = member.Value
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func jsonPathString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		raw, _ := json.Marshal(value)
+		return string(raw)
+	}
+}
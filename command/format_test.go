@@ -0,0 +1,39 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+func TestFormatYAML(t *testing.T) {
+	type someResult struct {
+		Zeta    string            `json:"zeta"`
+		Alpha   []string          `json:"alpha"`
+		Count   int               `json:"count"`
+		Ratio   float64           `json:"ratio"`
+		Enabled bool              `json:"enabled"`
+		Empty   map[string]string `json:"empty"`
+		Missing *string           `json:"missing"`
+	}
+	ambiguous := []string{"0x1F", "0o17", ".inf", "-.Inf", ".nan", "1e3", "012", "true", "no", "null", "~", "", " padded", "- dash", "a: b", "#comment"}
+	result := someResult{Zeta: "last", Alpha: ambiguous, Count: 3, Ratio: 0.5, Enabled: true, Empty: map[string]string{}}
+
+	var out strings.Builder
+	require.NoError(t, FormatYAML(&out, result, FormatOptions{}))
+	assert.True(t, strings.HasPrefix(out.String(), "zeta: last\nalpha:\n"), "keeps the order of the fields:\n%s", out.String())
+	assert.Contains(t, out.String(), "count: 3\nratio: 0.5\nenabled: true\nempty: {}\nmissing: null\n")
+
+	var decoded map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out.String()), &decoded))
+	alpha := make([]string, 0, len(ambiguous))
+	for _, element := range decoded["alpha"].([]any) {
+		s, ok := element.(string)
+		assert.Truef(t, ok, "element %v must be read back as string", element)
+		alpha = append(alpha, s)
+	}
+	assert.Equal(t, ambiguous, alpha)
+}
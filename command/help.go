@@ -0,0 +1,191 @@
+package command
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FlagGroup registers the flags and constraints of the given group with this command.
+// The help output shows the flags of the group under their own heading, such as "Connection Flags:",
+// unless the flag has its own [flag.RegisterOptions.Group].
+func (c Command) FlagGroup(group flag.Group) Command {
+	for _, groupFlag := range group.Flags {
+		options := groupFlag.Options
+		if options.Group == "" {
+			options.Group = group.Name
+		}
+		c = c.Flag(groupFlag.Value, options)
+	}
+	for _, targets := range group.RequiredTogether {
+		c = c.MarkFlagsRequiredTogether(targets...)
+	}
+	for _, targets := range group.OneRequired {
+		c = c.MarkFlagsOneRequired(targets...)
+	}
+	for _, targets := range group.MutuallyExclusive {
+		c = c.MarkFlagsMutuallyExclusive(targets...)
+	}
+	return c
+}
+
+// FlagGroups orders the sections of grouped flags in the help output of this command and its sub commands,
+// see [flag.RegisterOptions.Group]. Groups not given here are shown afterwards, ordered by their first flag.
+// The ungrouped flags are always shown first.
+func (c Command) FlagGroups(groups ...string) Command {
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{}
+	}
+	c.Annotations[flag.GroupAnnotation] = strings.Join(groups, "\n")
+	c.enableFlagSections()
+	return c
+}
+
+// SortFlags controls whether the flags of this command are sorted alphabetically in the help output,
+// which is the default. Use false to keep the order of registration.
+// Note that inherited flags are always sorted by Cobra.
+func (c Command) SortFlags(sort bool) Command {
+	c.Flags().SortFlags = sort
+	c.PersistentFlags().SortFlags = sort
+	return c
+}
+
+// CommandGroup adds a group for sub commands to this command, see [cobra.Command.AddGroup].
+// The help output shows the sub commands having the group ID (see GroupID) under the given title.
+func (c Command) CommandGroup(id, title string) Command {
+	c.AddGroup(&cobra.Group{ID: id, Title: title})
+	return c
+}
+
+// GroupID sets the ID of the group this command belongs to within its parent command, see CommandGroup.
+func (c Command) GroupID(id string) Command {
+	c.Command.GroupID = id
+	return c
+}
+
+const (
+	// flagSectionsTemplateFunc renders the local flags of a command, see flagSections.
+	flagSectionsTemplateFunc = "naginiFlagSections"
+	// defaultLocalFlagsTemplate is the part of Cobra's default usage template which is replaced by flagSections.
+	defaultLocalFlagsTemplate = `{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}`
+	// flagUsagesTemplateFunc renders the flag usages of a flag set, see flagUsages.
+	flagUsagesTemplateFunc = "naginiFlagUsages"
+	// defaultInheritedFlagsTemplate is the part of Cobra's default usage template which is replaced by flagUsages.
+	defaultInheritedFlagsTemplate = `{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}`
+)
+
+//nolint:gochecknoglobals
+var addFlagSectionsTemplateFunc = sync.OnceFunc(func() {
+	cobra.AddTemplateFunc(flagSectionsTemplateFunc, flagSections)
+	cobra.AddTemplateFunc(flagUsagesTemplateFunc, flagUsages)
+})
+
+// enableFlagSections replaces the local flags section of the usage template with flagSections
+// and the inherited flags with flagUsages.
+// Custom usage templates without the default sections are left untouched.
+func (c Command) enableFlagSections() {
+	addFlagSectionsTemplateFunc()
+	usageTemplate := c.UsageTemplate()
+	usageTemplate = strings.Replace(usageTemplate, defaultLocalFlagsTemplate, "{{"+flagSectionsTemplateFunc+" .}}", 1)
+	usageTemplate = strings.Replace(usageTemplate, defaultInheritedFlagsTemplate,
+		"{{"+flagUsagesTemplateFunc+" .InheritedFlags | trimTrailingWhitespaces}}", 1)
+	if usageTemplate != c.UsageTemplate() {
+		c.SetUsageTemplate(usageTemplate)
+	}
+}
+
+// flagSections renders the local flags like Cobra's default usage template,
+// but shows the flags belonging to a group in separate sections after the ungrouped flags.
+// The groups are ordered as given by FlagGroups of the command or its nearest parent, then by their first flag.
+func flagSections(cmd *cobra.Command) string {
+	localFlags := cmd.LocalFlags()
+	var groupNames []string
+	groupedFlags := map[string]*pflag.FlagSet{}
+	localFlags.VisitAll(func(f *pflag.Flag) {
+		var groupName string
+		if annotation := f.Annotations[flag.GroupAnnotation]; len(annotation) > 0 {
+			groupName = annotation[0]
+		}
+		flags, found := groupedFlags[groupName]
+		if !found {
+			flags = pflag.NewFlagSet(groupName, pflag.ContinueOnError)
+			flags.SortFlags = localFlags.SortFlags
+			groupedFlags[groupName] = flags
+			if groupName != "" {
+				groupNames = append(groupNames, groupName)
+			}
+		}
+		flags.AddFlag(f)
+	})
+	groupNames = orderGroupNames(cmd, groupNames)
+	var builder strings.Builder
+	writeSection := func(heading string, flags *pflag.FlagSet) {
+		if flags == nil || !flags.HasAvailableFlags() {
+			return
+		}
+		builder.WriteString("\n\n" + heading + ":\n")
+		builder.WriteString(strings.TrimRightFunc(flagUsages(flags), unicode.IsSpace))
+	}
+	writeSection("Flags", groupedFlags[""])
+	for _, groupName := range groupNames {
+		writeSection(groupName+" Flags", groupedFlags[groupName])
+	}
+	return builder.String()
+}
+
+// orderGroupNames orders the given group names as given by FlagGroups, see flagSections.
+func orderGroupNames(cmd *cobra.Command, groupNames []string) []string {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		order, found := cmd.Annotations[flag.GroupAnnotation]
+		if !found {
+			continue
+		}
+		var result []string
+		for _, groupName := range strings.Split(order, "\n") {
+			if slices.Contains(groupNames, groupName) {
+				result = append(result, groupName)
+			}
+		}
+		for _, groupName := range groupNames {
+			if !slices.Contains(result, groupName) {
+				result = append(result, groupName)
+			}
+		}
+		return result
+	}
+	return groupNames
+}
+
+// flagUsages renders the flag usages like [pflag.FlagSet.FlagUsages],
+// but shows negatable flags (see [flag.RegisterOptions.Negatable]) as "--[no-]<name>".
+// The usages are wrapped to the width given by helpWidth.
+func flagUsages(flags *pflag.FlagSet) string {
+	usages := flags.FlagUsagesWrapped(helpWidth())
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden && flag.IsNegatable(f) {
+			// keep the length to preserve the alignment of the usage column
+			usages = strings.Replace(usages, "--"+f.Name+"[=true] ", "--[no-]"+f.Name+"   ", 1)
+		}
+	})
+	return usages
+}
+
+// helpWidth returns the terminal width given by the environment variable COLUMNS, or 0 if not set.
+// Shells usually set COLUMNS, but do not export it, so the help output is only wrapped if requested explicitly.
+func helpWidth() int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
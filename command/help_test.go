@@ -0,0 +1,182 @@
+package command
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type someConnection struct {
+	host string
+	port int
+	tls  bool
+}
+
+func newSomeConnectionGroup(conn *someConnection) flag.Group {
+	return flag.NewGroup("Connection").
+		Flag(flag.String(&conn.host, flag.NotEmpty), flag.RegisterOptions{Name: "host", Usage: "Host to connect to"}).
+		Flag(flag.String(&conn.port, strconv.Atoi), flag.RegisterOptions{Name: "port", Usage: "Port to connect to"}).
+		Flag(flag.Bool(&conn.tls), flag.RegisterOptions{Name: "tls", Usage: "Use TLS"}).
+		MarkFlagsRequiredTogether(&conn.host, &conn.port)
+}
+
+func TestCommand_FlagGroup(t *testing.T) {
+	var (
+		conn1, conn2 someConnection
+		verbose      bool
+	)
+	sub1 := New().Use("sub1").FlagGroup(newSomeConnectionGroup(&conn1)).
+		Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Usage: "Be verbose"}).
+		Run(func() error { return nil })
+	sub2 := New().Use("sub2").FlagGroup(newSomeConnectionGroup(&conn2)).Run(func() error { return nil })
+	root := New().Use("app").AddCommands(sub1, sub2)
+
+	t.Run("separate storage", func(t *testing.T) {
+		require.NoError(t, root.Execute(WithArgs("sub1", "--host", "h1", "--port", "1"), AssertExitCode(t, 0)))
+		require.NoError(t, root.Execute(WithArgs("sub2", "--host", "h2", "--port", "2", "--tls"), AssertExitCode(t, 0)))
+		assert.Equal(t, someConnection{"h1", 1, false}, conn1)
+		assert.Equal(t, someConnection{"h2", 2, true}, conn2)
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		root.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, root.Execute(WithArgs("sub2", "--host", "h2"), WithReset(), AssertExitCode(t, 1)),
+			"if any flags in the group [host port] are set they must all be set; missing [port]")
+	})
+
+	t.Run("help output", func(t *testing.T) {
+		getStdout, _ := root.CaptureCobraOutput(t)
+		require.NoError(t, root.Execute(WithArgs("sub1", "--help"), WithReset(), AssertExitCode(t, 0)))
+		assert.Equal(t, `Usage:
+  app sub1 [flags]
+
+Flags:
+  -h, --help             help for sub1
+      --verbose[=true]   Be verbose
+
+Connection Flags:
+      --host string   Host to connect to
+      --port int      Port to connect to (default 0)
+      --tls[=true]    Use TLS
+`, getStdout())
+	})
+
+	t.Run("shared storage", func(t *testing.T) {
+		var conn someConnection
+		group := newSomeConnectionGroup(&conn)
+		root := New().Use("app").AddCommands(
+			New().Use("sub1").FlagGroup(group).Run(func() error { return nil }),
+			New().Use("sub2").FlagGroup(group).Run(func() error { return nil }),
+		)
+		require.NoError(t, root.Execute(WithArgs("sub2", "--host", "h", "--port", "1"), AssertExitCode(t, 0)))
+		assert.Equal(t, someConnection{"h", 1, false}, conn)
+	})
+}
+
+func TestCommand_FlagGroups(t *testing.T) {
+	var (
+		output, format, host string
+		verbose              bool
+	)
+	newCmd := func() Command {
+		return New().Use("app").
+			Flag(flag.String(&output, flag.NotEmpty), flag.RegisterOptions{Name: "output", Usage: "Output file", Group: "Output"}).
+			Flag(flag.String(&host, flag.NotEmpty), flag.RegisterOptions{Name: "host", Usage: "Host", Group: "Connection"}).
+			Flag(flag.String(&format, flag.NotEmpty), flag.RegisterOptions{Name: "format", Usage: "Output format", Group: "Output"}).
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Usage: "Be verbose"}).
+			Run(func() error { return nil })
+	}
+
+	t.Run("ordered by first sorted flag", func(t *testing.T) {
+		cmd := newCmd()
+		getStdout, _ := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--help"), AssertExitCode(t, 0)))
+		assert.Equal(t, `Usage:
+  app [flags]
+
+Flags:
+  -h, --help             help for app
+      --verbose[=true]   Be verbose
+
+Output Flags:
+      --format string   Output format
+      --output string   Output file
+
+Connection Flags:
+      --host string   Host
+`, getStdout())
+	})
+
+	t.Run("ordered by FlagGroups and registration", func(t *testing.T) {
+		cmd := newCmd().FlagGroups("Connection").SortFlags(false)
+		getStdout, _ := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--help"), AssertExitCode(t, 0)))
+		assert.Equal(t, `Usage:
+  app [flags]
+
+Flags:
+      --verbose[=true]   Be verbose
+  -h, --help             help for app
+
+Connection Flags:
+      --host string   Host
+
+Output Flags:
+      --output string   Output file
+      --format string   Output format
+`, getStdout())
+	})
+}
+
+func TestCommand_CommandGroup(t *testing.T) {
+	root := New().Use("app").
+		CommandGroup("manage", "Management Commands:").
+		AddCommands(
+			New().Use("create").Short("Create something").GroupID("manage").Run(func() error { return nil }),
+			New().Use("version").Short("Print version").Run(func() error { return nil }),
+		)
+	root.SetHelpCommand(&cobra.Command{Hidden: true})
+	root.CompletionOptions.DisableDefaultCmd = true
+	getStdout, _ := root.CaptureCobraOutput(t)
+	require.NoError(t, root.Execute(WithArgs("--help"), AssertExitCode(t, 0)))
+	assert.Equal(t, `Usage:
+  app [command]
+
+Management Commands:
+  create      Create something
+
+Additional Commands:
+  version     Print version
+
+Flags:
+  -h, --help   help for app
+
+Use "app [command] --help" for more information about a command.
+`, getStdout())
+}
+
+func TestCommand_helpWidth(t *testing.T) {
+	var name string
+	cmd := New().Use("app").
+		Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{
+			Name:  "name",
+			Usage: "Some rather long usage text which does not fit into a narrow terminal",
+		}).
+		Run(func() error { return nil })
+	getStdout, _ := cmd.CaptureCobraOutput(t)
+	t.Setenv("COLUMNS", "50")
+	require.NoError(t, cmd.Execute(WithArgs("--help"), WithReset(), AssertExitCode(t, 0)))
+	assert.Equal(t, `Usage:
+  app [flags]
+
+Flags:
+  -h, --help          help for app
+      --name string   Some rather long usage
+                      text which does not
+                      fit into a narrow terminal
+`, getStdout())
+}
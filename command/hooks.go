@@ -0,0 +1,102 @@
+package command
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// cobraHooks holds the hooks of a cobra.Command, see installHookChain.
+type cobraHooks struct {
+	persistentPreRunE, preRunE, postRunE, persistentPostRunE func(cmd *cobra.Command, args []string) error
+	persistentPreRun, preRun, postRun, persistentPostRun     func(cmd *cobra.Command, args []string)
+}
+
+// installHookChain makes every executed command run the persistent hooks of all its parents added via AddCommands,
+// as opposed to Cobra, which only runs the persistent hooks of the nearest command having one,
+// unless [cobra.EnableTraverseRunHooks] is set. The hooks run in the following order:
+//
+//  1. The persistent pre-runs from the root to the executed command,
+//     such as AddPersistentPreRun and the bindings of persistent flags.
+//  2. The pre-run of the executed command, such as the bindings of flags.
+//  3. The run of the executed command, see Run.
+//  4. The post-run of the executed command.
+//  5. The persistent post-runs from the executed command to the root.
+//
+// For each command and hook, the variant returning an error (such as PreRunE) takes precedence, as in Cobra.
+// As the chain is installed just before execution, hooks may also be set directly on the embedded cobra.Command.
+// The returned function restores the previous state.
+func (c Command) installHookChain() (restore func()) {
+	original := map[*cobra.Command]cobraHooks{}
+	for command := range c.All() {
+		for parent := range command.Parents() {
+			original[parent.Command] = getCobraHooks(parent.Command)
+		}
+	}
+	for cmd, hooks := range original {
+		// the persistent hooks are run by the chain instead
+		hooks.persistentPreRunE, hooks.persistentPreRun = nil, nil
+		hooks.persistentPostRunE, hooks.persistentPostRun = nil, nil
+		setCobraHooks(cmd, hooks)
+	}
+	for command := range c.All() {
+		parents := slices.Collect(command.Parents())
+		var pre func(cmd *cobra.Command, args []string) error
+		for _, parent := range slices.Backward(parents) {
+			hooks := original[parent.Command]
+			pre = chainCobraRun(pre, orCobraRun(hooks.persistentPreRunE, hooks.persistentPreRun))
+		}
+		hooks := original[command.Command]
+		pre = chainCobraRun(pre, orCobraRun(hooks.preRunE, hooks.preRun))
+		post := orCobraRun(hooks.postRunE, hooks.postRun)
+		for _, parent := range parents {
+			hooks := original[parent.Command]
+			post = chainCobraRun(post, orCobraRun(hooks.persistentPostRunE, hooks.persistentPostRun))
+		}
+		setCobraHooks(command.Command, cobraHooks{preRunE: pre, postRunE: post})
+	}
+	return func() {
+		for cmd, hooks := range original {
+			setCobraHooks(cmd, hooks)
+		}
+	}
+}
+
+// orCobraRun returns runE, or run if runE is nil, like Cobra does for each hook.
+// Returns nil if both are nil.
+func orCobraRun(
+	runE func(cmd *cobra.Command, args []string) error,
+	run func(cmd *cobra.Command, args []string),
+) func(cmd *cobra.Command, args []string) error {
+	if runE != nil || run == nil {
+		return runE
+	}
+	return func(cmd *cobra.Command, args []string) error {
+		run(cmd, args)
+		return nil
+	}
+}
+
+func getCobraHooks(cmd *cobra.Command) cobraHooks {
+	return cobraHooks{
+		persistentPreRunE:  cmd.PersistentPreRunE,
+		preRunE:            cmd.PreRunE,
+		postRunE:           cmd.PostRunE,
+		persistentPostRunE: cmd.PersistentPostRunE,
+		persistentPreRun:   cmd.PersistentPreRun,
+		preRun:             cmd.PreRun,
+		postRun:            cmd.PostRun,
+		persistentPostRun:  cmd.PersistentPostRun,
+	}
+}
+
+func setCobraHooks(cmd *cobra.Command, hooks cobraHooks) {
+	cmd.PersistentPreRunE = hooks.persistentPreRunE
+	cmd.PreRunE = hooks.preRunE
+	cmd.PostRunE = hooks.postRunE
+	cmd.PersistentPostRunE = hooks.persistentPostRunE
+	cmd.PersistentPreRun = hooks.persistentPreRun
+	cmd.PreRun = hooks.preRun
+	cmd.PostRun = hooks.postRun
+	cmd.PersistentPostRun = hooks.persistentPostRun
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_hookChain(t *testing.T) {
+	var (
+		calls    []string
+		someInt  int
+		boundInt int
+	)
+	record := func(call string) func() error {
+		return func() error {
+			calls = append(calls, call)
+			return nil
+		}
+	}
+	leaf := New().Use("leaf").
+		AddPersistentPreRun(record("leaf persistent pre-run")).
+		AddPersistentPostRun(record("leaf persistent post-run")).
+		Run(record("leaf run"))
+	leaf.PreRun = func(*cobra.Command, []string) {
+		calls = append(calls, "leaf pre-run")
+	}
+	leaf.PostRunE = func(*cobra.Command, []string) error {
+		calls = append(calls, "leaf post-run")
+		return nil
+	}
+	middle := New().Use("middle").AddCommands(leaf)
+	middle.PersistentPreRun = func(*cobra.Command, []string) {
+		calls = append(calls, "middle persistent pre-run")
+	}
+	root := New().Use("app").
+		Flag(flag.Env{Value: flag.String(&someInt, strconv.Atoi), EnvVar: "SOME_HOOK_CHAIN_INT"},
+			flag.RegisterOptions{Name: "some-int", Persistent: true}).
+		AddPersistentPreRun(func() error {
+			boundInt = someInt
+			return record("root persistent pre-run")()
+		}).
+		AddPersistentPostRun(record("root persistent post-run")).
+		AddCommands(middle)
+	t.Setenv("SOME_HOOK_CHAIN_INT", "42")
+
+	for _, traverse := range []bool{false, true} {
+		t.Run("traverse run hooks "+strconv.FormatBool(traverse), func(t *testing.T) {
+			previous := cobra.EnableTraverseRunHooks
+			cobra.EnableTraverseRunHooks = traverse
+			t.Cleanup(func() {
+				cobra.EnableTraverseRunHooks = previous
+			})
+			calls, boundInt = nil, 0
+			require.NoError(t, root.Execute(WithArgs("middle", "leaf"), WithReset(), AssertExitCode(t, 0)))
+			assert.Equal(t, []string{
+				"root persistent pre-run",
+				"middle persistent pre-run",
+				"leaf persistent pre-run",
+				"leaf pre-run",
+				"leaf run",
+				"leaf post-run",
+				"leaf persistent post-run",
+				"root persistent post-run",
+			}, calls)
+			assert.Equal(t, 42, boundInt, "persistent flag of root is bound before its pre-run")
+			assert.NotNil(t, root.PersistentPreRunE, "hooks are restored")
+			assert.Nil(t, leaf.PreRunE, "hooks are restored")
+		})
+	}
+}
@@ -22,17 +22,25 @@ func wrapRunCallbackError(run func() error) func(*cobra.Command, []string) error
 }
 
 func (c Command) addToPersistentPreRunE(action func(*cobra.Command, []string) error) {
-	// important to catch existing as local variable,
-	// as otherwise chaining the action callbacks leads to a stack overflow
-	if existing := c.PersistentPreRunE; existing != nil {
-		c.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-			if err := existing(cmd, args); err != nil {
-				return err
-			}
-			return action(cmd, args)
+	c.PersistentPreRunE = chainCobraRun(c.PersistentPreRunE, action)
+}
+
+// chainCobraRun returns a Cobra run callback which runs first and then second, if first did not fail.
+// The given callbacks may be nil.
+// Note that passing the callbacks as arguments captures them,
+// as otherwise chaining the action callbacks leads to a stack overflow.
+func chainCobraRun(first, second func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(cmd *cobra.Command, args []string) error {
+		if err := first(cmd, args); err != nil {
+			return err
 		}
-	} else {
-		c.PersistentPreRunE = action
+		return second(cmd, args)
 	}
 }
 
@@ -0,0 +1,201 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/pflag"
+)
+
+// A LintRule checks a single command of the tree, see Lint.
+type LintRule struct {
+	// Name identifies the rule in a LintFinding.
+	Name string
+	// Check returns a message for each mistake found in the given command.
+	Check func(cmd Command) []string
+}
+
+// A LintFinding is a mistake found by Lint.
+type LintFinding struct {
+	// CommandPath is the path of the command containing the mistake, see [github.com/spf13/cobra.Command.CommandPath].
+	CommandPath string
+	// Rule is the name of the LintRule reporting the mistake.
+	Rule string
+	// Message describes the mistake.
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.CommandPath, f.Rule, f.Message)
+}
+
+// Lint checks this command and all sub commands added via AddCommands with the given rules
+// and returns all findings in tree order.
+// Uses DefaultLintRules if no rules are given.
+// Lint catches configuration mistakes which otherwise only fail at runtime, such as conflicting shorthands.
+// Note that referencing unregistered targets in RequiredIf already panics during construction,
+// while the targets of MarkFlagsRequiredTogether and alike are resolved during Execute, see LintFlagConstraints.
+func (c Command) Lint(rules ...LintRule) (findings []LintFinding) {
+	if len(rules) == 0 {
+		rules = DefaultLintRules()
+	}
+	for command := range c.All() {
+		for _, rule := range rules {
+			for _, message := range rule.Check(command) {
+				findings = append(findings, LintFinding{command.CommandPath(), rule.Name, message})
+			}
+		}
+	}
+	return
+}
+
+// DefaultLintRules returns the rules catching mistakes which fail at runtime.
+// These are LintCommandUse, LintFlagNames, LintFlagConflicts and LintFlagConstraints.
+func DefaultLintRules() []LintRule {
+	return []LintRule{LintCommandUse(), LintFlagNames(), LintFlagConflicts(), LintFlagConstraints()}
+}
+
+// LintCommandUse reports commands without Use, which cannot be called as a sub command.
+func LintCommandUse() LintRule {
+	return LintRule{Name: "command-use", Check: func(cmd Command) (messages []string) {
+		if cmd.Name() == "" {
+			messages = append(messages, "command has empty Use")
+		}
+		return
+	}}
+}
+
+// LintFlagNames reports flags registered with an empty [flag.RegisterOptions.Name].
+func LintFlagNames() LintRule {
+	return LintRule{Name: "flag-names", Check: func(cmd Command) (messages []string) {
+		for _, f := range ownFlags(cmd) {
+			if f.Name == "" {
+				messages = append(messages, fmt.Sprintf("flag with usage %q has empty name", f.Usage))
+			}
+		}
+		return
+	}}
+}
+
+// LintFlagConflicts reports flags which shadow an inherited persistent flag of a parent command
+// or use the same shorthand as an inherited flag.
+// It also reports local and persistent flags of the same command having the same name or shorthand.
+// Both make Cobra panic when parsing the flags.
+func LintFlagConflicts() LintRule {
+	return LintRule{Name: "flag-conflicts", Check: func(cmd Command) (messages []string) {
+		inheritedByName, inheritedByShorthand := map[string]*pflag.Flag{}, map[string]*pflag.Flag{}
+		for _, f := range inheritedFlags(cmd) {
+			inheritedByName[f.Name] = f
+			if f.Shorthand != "" {
+				inheritedByShorthand[f.Shorthand] = f
+			}
+		}
+		ownByName, ownByShorthand := map[string]*pflag.Flag{}, map[string]*pflag.Flag{}
+		for _, f := range ownFlags(cmd) {
+			if _, found := inheritedByName[f.Name]; found {
+				messages = append(messages, fmt.Sprintf("flag --%s shadows inherited flag", f.Name))
+			} else if inherited, found := inheritedByShorthand[f.Shorthand]; found {
+				messages = append(messages, fmt.Sprintf("flag --%s uses shorthand -%s of inherited flag --%s",
+					f.Name, f.Shorthand, inherited.Name))
+			} else if _, found := ownByName[f.Name]; found {
+				messages = append(messages, fmt.Sprintf("flag --%s is registered as local and persistent flag", f.Name))
+			} else if other, found := ownByShorthand[f.Shorthand]; found {
+				messages = append(messages, fmt.Sprintf("flag --%s uses shorthand -%s of flag --%s",
+					f.Name, f.Shorthand, other.Name))
+			}
+			ownByName[f.Name] = f
+			if f.Shorthand != "" {
+				ownByShorthand[f.Shorthand] = f
+			}
+		}
+		return
+	}}
+}
+
+// LintFlagConstraints reports targets of flag constraints which cannot be resolved to flags,
+// such as the targets given to MarkFlagsMutuallyExclusive which have not been registered with Flag.
+// Execute panics for these constraints.
+// Targets of persistent constraints, such as MarkPersistentFlagsOneRequired,
+// are reported if no command of the tree starting at the marked command has a flag registered for them.
+func LintFlagConstraints() LintRule {
+	return LintRule{Name: "flag-constraints", Check: func(cmd Command) (messages []string) {
+		for _, constraint := range *cmd.constraints {
+			for i, target := range constraint.targets {
+				resolved := false
+				for command := range cmd.All() {
+					if _, err := command.lookupFlagNames([]any{target}); err == nil {
+						resolved = true
+						break
+					}
+					if !constraint.persistent {
+						break
+					}
+				}
+				if !resolved {
+					messages = append(messages, fmt.Sprintf("flag constraint references target #%d of type %T without registered flag", i+1, target))
+				}
+			}
+		}
+		return
+	}}
+}
+
+// LintFlagUsage reports visible flags without [flag.RegisterOptions.Usage].
+func LintFlagUsage() LintRule {
+	return LintRule{Name: "flag-usage", Check: func(cmd Command) (messages []string) {
+		for _, f := range ownFlags(cmd) {
+			if f.Usage == "" && !f.Hidden {
+				messages = append(messages, fmt.Sprintf("flag --%s has empty usage", f.Name))
+			}
+		}
+		return
+	}}
+}
+
+// LintKebabCase reports command and flag names which are not in kebab-case, such as "dry-run".
+func LintKebabCase() LintRule {
+	kebabCaseRegex := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	return LintRule{Name: "kebab-case", Check: func(cmd Command) (messages []string) {
+		if name := cmd.Name(); name != "" && !kebabCaseRegex.MatchString(name) {
+			messages = append(messages, fmt.Sprintf("command name %q is not kebab-case", name))
+		}
+		for _, f := range ownFlags(cmd) {
+			if f.Name != "" && !kebabCaseRegex.MatchString(f.Name) {
+				messages = append(messages, fmt.Sprintf("flag --%s is not kebab-case", f.Name))
+			}
+		}
+		return
+	}}
+}
+
+// ownFlags returns the local and persistent flags registered on the given command.
+// Avoids merging the persistent flags of the parents, as this panics on conflicting shorthands.
+func ownFlags(cmd Command) (result []*pflag.Flag) {
+	inherited := map[*pflag.Flag]bool{}
+	for _, f := range inheritedFlags(cmd) {
+		inherited[f] = true
+	}
+	seen := map[*pflag.Flag]bool{}
+	visit := func(f *pflag.Flag) {
+		if !inherited[f] && !seen[f] {
+			seen[f] = true
+			result = append(result, f)
+		}
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.PersistentFlags().VisitAll(visit)
+	return
+}
+
+// inheritedFlags returns the persistent flags of all parent commands.
+func inheritedFlags(cmd Command) (result []*pflag.Flag) {
+	for parent := range cmd.Parents() {
+		if parent.Command == cmd.Command {
+			continue
+		}
+		parent.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+			result = append(result, f)
+		})
+	}
+	return
+}
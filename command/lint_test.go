@@ -0,0 +1,88 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand_Lint(t *testing.T) {
+	newCommandTree := func() Command {
+		var (
+			verbose, version, dryRun bool
+			name, empty              string
+		)
+		return New().Use("app").
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Shorthand: "v", Persistent: true}).
+			AddCommands(
+				New().Use("sub").
+					Flag(flag.Bool(&version), flag.RegisterOptions{Name: "version", Shorthand: "v", Usage: "Show version"}).
+					Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "verbose", Usage: "Shadows"}).
+					Flag(flag.String(&empty, flag.NotEmpty), flag.RegisterOptions{Usage: "No name"}),
+				New().Use("Other").
+					Flag(flag.Bool(&dryRun), flag.RegisterOptions{Name: "dryRun", Usage: "Not kebab-case"}),
+				New(),
+			)
+	}
+
+	t.Run("default rules", func(t *testing.T) {
+		assert.Equal(t, []LintFinding{
+			{"app sub", "flag-names", `flag with usage "No name" has empty name`},
+			{"app sub", "flag-conflicts", "flag --verbose shadows inherited flag"},
+			{"app sub", "flag-conflicts", "flag --version uses shorthand -v of inherited flag --verbose"},
+			{"app ", "command-use", "command has empty Use"},
+		}, newCommandTree().Lint())
+	})
+
+	t.Run("configured rules", func(t *testing.T) {
+		findings := newCommandTree().Lint(LintFlagUsage(), LintKebabCase())
+		assert.Equal(t, []LintFinding{
+			{"app", "flag-usage", "flag --verbose has empty usage"},
+			{"app Other", "kebab-case", `command name "Other" is not kebab-case`},
+			{"app Other", "kebab-case", "flag --dryRun is not kebab-case"},
+		}, findings)
+		assert.Equal(t, "app Other: kebab-case: flag --dryRun is not kebab-case", findings[2].String())
+	})
+
+	t.Run("conflicts between local and persistent flags", func(t *testing.T) {
+		var (
+			output, format, debug string
+		)
+		cmd := New().Use("app").
+			Flag(flag.String(&output, flag.NotEmpty), flag.RegisterOptions{Name: "output", Shorthand: "o"}).
+			Flag(flag.String(&format, flag.NotEmpty), flag.RegisterOptions{Name: "format", Shorthand: "o", Persistent: true}).
+			Flag(flag.String(&debug, flag.NotEmpty), flag.RegisterOptions{Name: "output", Persistent: true})
+		assert.Equal(t, []LintFinding{
+			{"app", "flag-conflicts", "flag --format uses shorthand -o of flag --output"},
+			{"app", "flag-conflicts", "flag --output is registered as local and persistent flag"},
+		}, cmd.Lint(LintFlagConflicts()))
+	})
+
+	t.Run("unresolved flag constraints", func(t *testing.T) {
+		var (
+			verbose, quiet, unknown bool
+		)
+		cmd := New().Use("app").
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Persistent: true}).
+			MarkPersistentFlagsOneRequired(&verbose, &quiet).
+			AddCommands(
+				New().Use("sub").
+					Flag(flag.Bool(&quiet), flag.RegisterOptions{Name: "quiet"}).
+					MarkFlagsMutuallyExclusive(&verbose, &quiet, &unknown),
+			).
+			MarkPersistentFlagsRequiredTogether(&verbose, &unknown)
+		assert.Equal(t, []LintFinding{
+			{"app", "flag-constraints", "flag constraint references target #2 of type *bool without registered flag"},
+			{"app sub", "flag-constraints", "flag constraint references target #3 of type *bool without registered flag"},
+		}, cmd.Lint())
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		var verbose bool
+		cmd := New().Use("app").
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Shorthand: "v", Usage: "Be verbose", Persistent: true}).
+			AddCommands(New().Use("sub"))
+		assert.Empty(t, cmd.Lint(append(DefaultLintRules(), LintFlagUsage(), LintKebabCase())...))
+	})
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FlagHandle provides access to the flag registered for a target pointer, see Command.Lookup.
+type FlagHandle struct {
+	cmd   *cobra.Command
+	names []string
+}
+
+// Lookup returns the handle of the flag registered for the given target pointer via Flag.
+// Use it in Run callbacks to find out if the flag has been set explicitly, see FlagHandle.Changed.
+// Panics if no flag has been registered for the target.
+func (c Command) Lookup(target any) FlagHandle {
+	return FlagHandle{c.Command, c.getFlagNames([]any{target})}
+}
+
+// Names returns the names of the flags registered for the target.
+// There is usually only one name, unless the same target has been registered several times.
+func (h FlagHandle) Names() []string {
+	return h.names
+}
+
+// PFlag returns the underlying flag, or the first one if several names have been registered, see Names.
+func (h FlagHandle) PFlag() *pflag.Flag {
+	if flags := h.flags(); len(flags) > 0 {
+		return flags[0]
+	}
+	return nil
+}
+
+// Changed returns true if the flag has been set on the command line, including its aliases.
+func (h FlagHandle) Changed() bool {
+	for _, f := range h.flags() {
+		if f.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultString returns the default value of the flag as shown in the help output.
+func (h FlagHandle) DefaultString() string {
+	if f := h.PFlag(); f != nil {
+		return f.DefValue
+	}
+	return ""
+}
+
+// Source returns where the value of the flag originates from, see [flag.SourceOf].
+// Only meaningful after the bindings have been run, that is in Run or Validate callbacks.
+func (h FlagHandle) Source() flag.Source {
+	source := flag.SourceDefault
+	for _, f := range h.flags() {
+		if source = flag.SourceOf(f); source != flag.SourceDefault {
+			return source
+		}
+	}
+	return source
+}
+
+func (h FlagHandle) flags() (result []*pflag.Flag) {
+	for _, name := range h.names {
+		if f := h.cmd.Flag(name); f != nil {
+			result = append(result, f)
+		}
+	}
+	return
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Lookup(t *testing.T) {
+	var (
+		name    = "default"
+		retries = 3
+		verbose bool
+	)
+	cmd := New().
+		Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "name"}.Apply(flag.WithAliases("n"))).
+		Flag(flag.Env{Value: flag.String(&retries, strconv.Atoi), EnvVar: "SOME_LOOKUP_RETRIES"}, flag.RegisterOptions{Name: "retries"}).
+		Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Persistent: true})
+
+	var got map[string]flag.Source
+	cmd = cmd.Run(func() error {
+		got = map[string]flag.Source{}
+		for flagName, target := range map[string]any{"name": &name, "retries": &retries, "verbose": &verbose} {
+			got[flagName] = cmd.Lookup(target).Source()
+		}
+		return nil
+	})
+
+	t.Run("handle", func(t *testing.T) {
+		handle := cmd.Lookup(&name)
+		assert.Equal(t, []string{"name"}, handle.Names())
+		assert.Equal(t, "name", handle.PFlag().Name)
+		assert.Equal(t, "default", handle.DefaultString())
+		assert.False(t, handle.Changed())
+		assert.Equal(t, "verbose", cmd.Lookup(&verbose).PFlag().Name)
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		t.Setenv("SOME_LOOKUP_RETRIES", "5")
+		require.NoError(t, cmd.Execute(WithArgs("--n", "some name"), WithReset(), AssertExitCode(t, 0)))
+		assert.True(t, cmd.Lookup(&name).Changed())
+		assert.Equal(t, map[string]flag.Source{
+			"name": flag.SourceFlag, "retries": flag.SourceEnv, "verbose": flag.SourceDefault,
+		}, got)
+	})
+
+	t.Run("reset clears sources", func(t *testing.T) {
+		require.NoError(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 0)))
+		assert.False(t, cmd.Lookup(&name).Changed())
+		assert.Equal(t, map[string]flag.Source{
+			"name": flag.SourceDefault, "retries": flag.SourceDefault, "verbose": flag.SourceDefault,
+		}, got)
+	})
+
+	t.Run("panics with unknown target", func(t *testing.T) {
+		var unknown string
+		assert.Panics(t, func() {
+			cmd.Lookup(&unknown)
+		})
+	})
+}
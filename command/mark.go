@@ -1,52 +1,169 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"unsafe"
+
+	"github.com/spf13/cobra"
 )
 
-func getPointerValue(target any) uintptr {
+// getTargetKey returns the key of the given target pointer for the flagNames of a Command.
+// Uses the address only, as targets of different pointer types may point to the same value,
+// for example when using [flag.FromStruct] with types based on string.
+// As opposed to an uintptr, an unsafe.Pointer keeps the target alive.
+func getTargetKey(target any) unsafe.Pointer {
 	valueOf := reflect.ValueOf(target)
 	if valueOf.Kind() != reflect.Pointer {
 		panic(fmt.Sprintf("given target must be of type pointer, but is of type %s (value '%v')",
 			reflect.TypeOf(target), target))
 	}
-	return uintptr(valueOf.UnsafePointer())
+	return valueOf.UnsafePointer()
 }
 
 func (c Command) addFlagName(target any, flagName string) {
-	key := getPointerValue(target)
+	key := getTargetKey(target)
 	c.flagNames[key] = append(c.flagNames[key], flagName)
 }
 
-func (c Command) getFlagNames(targets []any) (result []string) {
+// getFlagNames resolves the flag names registered for the given targets via Flag.
+// Targets not registered with this command are looked up in the persistent flags of the parents.
+// Panics if a target cannot be found.
+func (c Command) getFlagNames(targets []any) []string {
+	result, err := c.lookupFlagNames(targets)
+	if err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// errUnknownTarget is returned by lookupFlagNames.
+var errUnknownTarget = errors.New("cannot find flag names for target pointer, did you register with Flag(...) first?")
+
+// lookupFlagNames is like getFlagNames, but returns errUnknownTarget instead of panicking.
+func (c Command) lookupFlagNames(targets []any) (result []string, err error) {
 	for _, target := range targets {
-		flagNames, found := c.flagNames[getPointerValue(target)]
-		if !found {
-			panic("cannot find flag names for target pointer, did you register with Flag(...) first?")
+		flagNames := c.flagNames[getTargetKey(target)]
+		for parent := range c.Parents() {
+			if len(flagNames) > 0 {
+				break
+			}
+			for _, flagName := range parent.flagNames[getTargetKey(target)] {
+				if parent.PersistentFlags().Lookup(flagName) != nil {
+					flagNames = append(flagNames, flagName)
+				}
+			}
+		}
+		if len(flagNames) == 0 {
+			return nil, errUnknownTarget
 		}
 		result = append(result, flagNames...)
 	}
 	return
 }
 
+// flagConstraint is a constraint which could not be applied yet,
+// or should be applied to all sub commands, see markFlags.
+type flagConstraint struct {
+	mark       func(cmd *cobra.Command, flagNames ...string)
+	targets    []any
+	persistent bool
+	// applied holds the commands the constraint has already been applied to.
+	applied map[*cobra.Command]bool
+}
+
+// markFlags applies the given cobra mark function to the flags of the given targets.
+// If the targets cannot be resolved yet, as the command has not been added to its parent via AddCommands,
+// the constraint is applied when executing, see applyFlagConstraints.
+func (c Command) markFlags(mark func(cmd *cobra.Command, flagNames ...string), targets []any) Command {
+	if flagNames, err := c.lookupFlagNames(targets); err == nil {
+		mark(c.Command, flagNames...)
+	} else {
+		*c.constraints = append(*c.constraints, &flagConstraint{mark: mark, targets: targets})
+	}
+	return c
+}
+
+// markPersistentFlags records the constraint to be applied to this command and all sub commands,
+// see applyFlagConstraints.
+func (c Command) markPersistentFlags(mark func(cmd *cobra.Command, flagNames ...string), targets []any) Command {
+	*c.constraints = append(*c.constraints, &flagConstraint{mark: mark, targets: targets, persistent: true})
+	return c
+}
+
+// applyFlagConstraints applies the constraints of markFlags and markPersistentFlags
+// to this command and all sub commands before execution.
+// Persistent constraints are skipped for commands which do not have all flags of the targets.
+// Panics if a constraint which is not persistent cannot be resolved.
+func (c Command) applyFlagConstraints() {
+	for command := range c.All() {
+		for parent := range command.Parents() {
+			for _, constraint := range *parent.constraints {
+				if parent.Command != command.Command && !constraint.persistent || constraint.applied[command.Command] {
+					continue
+				}
+				flagNames, err := command.lookupFlagNames(constraint.targets)
+				if err != nil {
+					if constraint.persistent {
+						continue
+					}
+					panic(err.Error())
+				}
+				constraint.mark(command.Command, flagNames...)
+				if constraint.applied == nil {
+					constraint.applied = map[*cobra.Command]bool{}
+				}
+				constraint.applied[command.Command] = true
+			}
+		}
+	}
+}
+
 // MarkFlagsRequiredTogether exposes [github.com/spf13/cobra.Command.MarkFlagsRequiredTogether] fluently,
 // accepting pointers to already registered flag values via Flag.
+// The targets may also be registered as persistent flags of the parents, see AddCommands.
+// Therefore, targets are resolved when executing at the latest, and Execute panics for targets which are not registered.
+// Use Lint with LintFlagConstraints to catch such targets in a test.
 func (c Command) MarkFlagsRequiredTogether(targets ...any) Command {
-	c.Command.MarkFlagsRequiredTogether(c.getFlagNames(targets)...)
-	return c
+	return c.markFlags((*cobra.Command).MarkFlagsRequiredTogether, targets)
 }
 
 // MarkFlagsOneRequired exposes [github.com/spf13/cobra.Command.MarkFlagsOneRequired] fluently,
 // accepting pointers to already registered flag values via Flag.
+// The targets may also be registered as persistent flags of the parents, see AddCommands.
+// Therefore, targets are resolved when executing at the latest, and Execute panics for targets which are not registered.
+// Use Lint with LintFlagConstraints to catch such targets in a test.
 func (c Command) MarkFlagsOneRequired(targets ...any) Command {
-	c.Command.MarkFlagsOneRequired(c.getFlagNames(targets)...)
-	return c
+	return c.markFlags((*cobra.Command).MarkFlagsOneRequired, targets)
 }
 
 // MarkFlagsMutuallyExclusive exposes [github.com/spf13/cobra.Command.MarkFlagsMutuallyExclusive] fluently,
 // accepting pointers to already registered flag values via Flag.
+// The targets may also be registered as persistent flags of the parents, see AddCommands.
+// Therefore, targets are resolved when executing at the latest, and Execute panics for targets which are not registered.
+// Use Lint with LintFlagConstraints to catch such targets in a test.
 func (c Command) MarkFlagsMutuallyExclusive(targets ...any) Command {
-	c.Command.MarkFlagsMutuallyExclusive(c.getFlagNames(targets)...)
-	return c
+	return c.markFlags((*cobra.Command).MarkFlagsMutuallyExclusive, targets)
+}
+
+// MarkPersistentFlagsRequiredTogether is like MarkFlagsRequiredTogether,
+// but applies to this command and all sub commands having flags registered for all targets.
+// This is useful for targets registered with several sub commands, see for example FlagGroup.
+func (c Command) MarkPersistentFlagsRequiredTogether(targets ...any) Command {
+	return c.markPersistentFlags((*cobra.Command).MarkFlagsRequiredTogether, targets)
+}
+
+// MarkPersistentFlagsOneRequired is like MarkFlagsOneRequired,
+// but applies to this command and all sub commands having flags registered for all targets.
+// This is useful for targets registered with several sub commands, see for example FlagGroup.
+func (c Command) MarkPersistentFlagsOneRequired(targets ...any) Command {
+	return c.markPersistentFlags((*cobra.Command).MarkFlagsOneRequired, targets)
+}
+
+// MarkPersistentFlagsMutuallyExclusive is like MarkFlagsMutuallyExclusive,
+// but applies to this command and all sub commands having flags registered for all targets.
+// This is useful for targets registered with several sub commands, see for example FlagGroup.
+func (c Command) MarkPersistentFlagsMutuallyExclusive(targets ...any) Command {
+	return c.markPersistentFlags((*cobra.Command).MarkFlagsMutuallyExclusive, targets)
 }
@@ -124,3 +124,55 @@ func TestCommand_MarkFlagsMutuallyExclusive(t *testing.T) {
 		require.True(t, flag2)
 	})
 }
+
+func TestCommand_MarkFlags_acrossHierarchy(t *testing.T) {
+	var (
+		verbose, quiet, json bool
+	)
+	t.Run("persistent flag of parent", func(t *testing.T) {
+		sub := New().Use("sub").
+			Flag(flag.Bool(&quiet), flag.RegisterOptions{Name: "quiet"}).
+			MarkFlagsMutuallyExclusive(&verbose, &quiet).
+			Run(func() error { return nil })
+		root := New().Use("app").
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Persistent: true}).
+			AddCommands(sub)
+		root.CaptureCobraOutput(t) // avoid confusing test output
+		require.NoError(t, root.Execute(WithArgs("sub", "--quiet"), WithReset(), AssertExitCode(t, 0)))
+		require.ErrorContains(t, root.Execute(WithArgs("sub", "--verbose", "--quiet"), WithReset(), AssertExitCode(t, 1)),
+			"if any flags in the group [verbose quiet] are set none of the others can be; [quiet verbose] were all set")
+		// resolved during execution, as the command is added to its parent afterwards
+		other := New().Use("other").Flag(flag.Bool(&json), flag.RegisterOptions{Name: "json"}).Run(func() error { return nil })
+		root.AddCommands(other.MarkFlagsOneRequired(&verbose, &json))
+		require.ErrorContains(t, root.Execute(WithArgs("other"), WithReset(), AssertExitCode(t, 1)),
+			"at least one of the flags in the group [verbose json] is required")
+	})
+
+	t.Run("persistent constraint", func(t *testing.T) {
+		newSub := func(use string) Command {
+			return New().Use(use).
+				Flag(flag.Bool(&quiet), flag.RegisterOptions{Name: "quiet"}).
+				Flag(flag.Bool(&json), flag.RegisterOptions{Name: "json"}).
+				Run(func() error { return nil })
+		}
+		root := New().Use("app").
+			Flag(flag.Bool(&verbose), flag.RegisterOptions{Name: "verbose", Persistent: true}).
+			AddCommands(newSub("sub1"), newSub("sub2"), New().Use("sub3").Run(func() error { return nil })).
+			MarkPersistentFlagsMutuallyExclusive(&verbose, &quiet)
+		root.CaptureCobraOutput(t) // avoid confusing test output
+		for _, sub := range []string{"sub1", "sub2"} {
+			require.ErrorContains(t, root.Execute(WithArgs(sub, "--verbose", "--quiet"), WithReset(), AssertExitCode(t, 1)),
+				"if any flags in the group [verbose quiet] are set none of the others can be", sub)
+		}
+		require.NoError(t, root.Execute(WithArgs("sub3", "--verbose"), WithReset(), AssertExitCode(t, 0)))
+		require.NoError(t, root.Execute(WithArgs("sub1", "--verbose", "--json"), WithReset(), AssertExitCode(t, 0)))
+	})
+
+	t.Run("unknown target panics during execution", func(t *testing.T) {
+		var unknown bool
+		cmd := New().Flag(flag.Bool(&quiet), flag.RegisterOptions{Name: "quiet"}).MarkFlagsMutuallyExclusive(&quiet, &unknown)
+		assert.PanicsWithValue(t, "cannot find flag names for target pointer, did you register with Flag(...) first?", func() {
+			_ = cmd.Execute(WithArgs(), WithExiter(func(int) {}))
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+)
+
+// Formatter renders the result of a command to the given writer, see RunWithResult and WithFormatter.
+type Formatter func(w io.Writer, result any, options FormatOptions) error
+
+// FormatOptions are given to a Formatter.
+type FormatOptions struct {
+	// Arg is the text after "=" of the output flag, such as the template of "--output template={{.Name}}".
+	Arg string
+	// NoHeaders is set by the flag --no-headers, see FormatTable.
+	NoHeaders bool
+}
+
+// OutputOption customizes RunWithResult.
+type OutputOption func(options *outputOptions)
+
+type outputOptions struct {
+	names           []string
+	formatters      map[string]Formatter
+	defaultFormat   string
+	outputFlag      string
+	outputShorthand string
+}
+
+// WithFormatter adds the given Formatter, or replaces the one with the same name, see RunWithResult.
+func WithFormatter(name string, formatter Formatter) OutputOption {
+	return func(options *outputOptions) {
+		if !slices.Contains(options.names, name) {
+			options.names = append(options.names, name)
+		}
+		options.formatters[name] = formatter
+	}
+}
+
+// WithOutputFlag registers the output flag with the given name and shorthand instead of --output (-o).
+// Use an empty shorthand to register the flag without one.
+func WithOutputFlag(name, shorthand string) OutputOption {
+	return func(options *outputOptions) {
+		options.outputFlag = name
+		options.outputShorthand = shorthand
+	}
+}
+
+// WithDefaultFormat uses the Formatter with the given name if the output flag is not set.
+// The default is "table".
+func WithDefaultFormat(name string) OutputOption {
+	return func(options *outputOptions) {
+		options.defaultFormat = name
+	}
+}
+
+// RunWithResult sets the given code to run during Execute, see Command.Run.
+// The returned result is rendered to the output of the command, see [cobra.Command.OutOrStdout],
+// in the format selected with the registered flag --output (-o). The following formats are supported:
+//
+//   - table: A table with one row per element if the result is a slice, see FormatTable. This is the default.
+//   - json: Indented JSON, see [encoding/json].
+//   - yaml: YAML, using the same field names as JSON.
+//   - template=TEMPLATE: A [text/template] executed with the result, such as "template={{.Name}}".
+//   - jsonpath=EXPRESSION: The fields selected by the expression, see FormatJSONPath.
+//
+// The flag --no-headers omits the headers of tables. Use WithFormatter to add custom formats.
+// Use WithOutputFlag if this command has a flag --output already. The shorthand is omitted if -o is used already.
+// Panics if the default format is unknown, or if the output flag or --no-headers is registered already.
+func RunWithResult[T any](c Command, run func(ctx context.Context) (T, error), options ...OutputOption) Command {
+	opts := outputOptions{formatters: map[string]Formatter{}, defaultFormat: "table", outputFlag: "output", outputShorthand: "o"}
+	for _, option := range append([]OutputOption{
+		WithFormatter("table", FormatTable),
+		WithFormatter("json", FormatJSON),
+		WithFormatter("yaml", FormatYAML),
+		WithFormatter("template", FormatTemplate),
+		WithFormatter("jsonpath", FormatJSONPath),
+	}, options...) {
+		option(&opts)
+	}
+	if _, found := opts.formatters[opts.defaultFormat]; !found {
+		panic(fmt.Sprintf("unknown default format '%s'", opts.defaultFormat))
+	}
+	for _, flagName := range []string{opts.outputFlag, noHeadersFlagName} {
+		if c.Flags().Lookup(flagName) != nil || c.PersistentFlags().Lookup(flagName) != nil {
+			panic(fmt.Sprintf("flag --%s is registered already, see WithOutputFlag", flagName))
+		}
+	}
+	if c.Flags().ShorthandLookup(opts.outputShorthand) != nil || c.PersistentFlags().ShorthandLookup(opts.outputShorthand) != nil {
+		opts.outputShorthand = ""
+	}
+	output := &outputFormat{Name: opts.defaultFormat}
+	var noHeaders bool
+	c = c.
+		Flag(outputValue{output, opts.names}, flag.RegisterOptions{
+			Name:      opts.outputFlag,
+			Shorthand: opts.outputShorthand,
+			Usage:     "Output format, one of " + strings.Join(opts.names, ", "),
+		}).
+		Flag(flag.Bool(&noHeaders), flag.RegisterOptions{Name: noHeadersFlagName, Usage: "Omit the headers of tables"})
+	c.RunE = wrapRunCallbackError(func() error {
+		result, err := run(c.Context())
+		if err != nil {
+			return err
+		}
+		formatter := opts.formatters[output.Name]
+		if err := formatter(c.OutOrStdout(), result, FormatOptions{Arg: output.Arg, NoHeaders: noHeaders}); err != nil {
+			return fmt.Errorf("cannot format result as %s: %w", output.Name, err)
+		}
+		return nil
+	})
+	return c
+}
+
+// noHeadersFlagName is the flag registered by RunWithResult, see FormatOptions.NoHeaders.
+const noHeadersFlagName = "no-headers"
+
+// outputFormat is the target of outputValue.
+type outputFormat struct {
+	Name string
+	Arg  string
+}
+
+// outputValue is the value of the output flag, see RunWithResult.
+// Accepts the name of a Formatter, optionally followed by "=" and an argument, see FormatOptions.Arg.
+// Implements flag.EnumValue and flag.Completer.
+type outputValue struct {
+	target *outputFormat
+	names  []string
+}
+
+func (v outputValue) Target() any {
+	return v.target
+}
+
+func (v outputValue) String() string {
+	if v.target.Arg != "" {
+		return v.target.Name + "=" + v.target.Arg
+	}
+	return v.target.Name
+}
+
+func (v outputValue) Set(s string) error {
+	name, arg, _ := strings.Cut(s, "=")
+	if !slices.Contains(v.names, name) {
+		return fmt.Errorf("%w: format '%s' must be one of %s", flag.ErrParser, name, strings.Join(v.names, ", "))
+	}
+	*v.target = outputFormat{name, arg}
+	return nil
+}
+
+func (v outputValue) Type() string {
+	return "format"
+}
+
+func (v outputValue) IsBoolFlag() bool {
+	return false
+}
+
+func (v outputValue) EnumValues() []string {
+	return v.names
+}
+
+func (v outputValue) Complete(string) ([]string, cobra.ShellCompDirective) {
+	return v.names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputTestItem struct {
+	Name    string   `json:"name"           table:"NAME"`
+	Size    int      `json:"size"`
+	Tags    []string `json:"tags,omitempty" table:"TAGS"`
+	Private string   `json:"-"              table:"-"`
+}
+
+func TestRunWithResult(t *testing.T) {
+	items := []outputTestItem{
+		{Name: "first", Size: 1, Tags: []string{"a", "b"}, Private: "hidden"},
+		{Name: "second: long", Size: 1024},
+	}
+	cmd := RunWithResult(New().Use("list"), func(context.Context) ([]outputTestItem, error) {
+		return items, nil
+	})
+	getStdout, _ := cmd.CaptureCobraOutput(t)
+
+	execute := func(t *testing.T, args ...string) string {
+		t.Helper()
+		stdout := getStdout()
+		require.NoError(t, cmd.Execute(WithArgs(args...), WithReset(), AssertExitCode(t, 0)))
+		return getStdout()[len(stdout):]
+	}
+
+	t.Run("table", func(t *testing.T) {
+		assert.Equal(t, ""+
+			"NAME           SIZE   TAGS\n"+
+			"first          1      a,b\n"+
+			"second: long   1024   \n", execute(t))
+	})
+
+	t.Run("table without headers", func(t *testing.T) {
+		assert.Equal(t, ""+
+			"first          1      a,b\n"+
+			"second: long   1024   \n", execute(t, "--no-headers"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert.JSONEq(t, `[{"name":"first","size":1,"tags":["a","b"]},{"name":"second: long","size":1024}]`,
+			execute(t, "-o", "json"))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		assert.Equal(t, ""+
+			"- name: first\n"+
+			"  size: 1\n"+
+			"  tags:\n"+
+			"    - a\n"+
+			"    - b\n"+
+			"- name: 'second: long'\n"+
+			"  size: 1024\n", execute(t, "--output", "yaml"))
+	})
+
+	t.Run("template", func(t *testing.T) {
+		assert.Equal(t, "first,second: long,", execute(t, "-o", "template={{range .}}{{.Name}},{{end}}"))
+	})
+
+	t.Run("jsonpath", func(t *testing.T) {
+		assert.Equal(t, "first second: long\n", execute(t, "-o", "jsonpath={[*].name}"))
+		assert.Equal(t, "b\n", execute(t, "-o", "jsonpath=[0].tags[-1]"))
+		assert.Equal(t, "size=1024\n", execute(t, "-o", "jsonpath=size={[1].size}"))
+		assert.Equal(t, "[\"a\",\"b\"]\n", execute(t, "-o", "jsonpath={[0].tags}"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.ErrorContains(t, cmd.Execute(WithArgs("-o", "xml"), WithReset(), AssertExitCode(t, 1)),
+			"format 'xml' must be one of table, json, yaml, template, jsonpath")
+		require.ErrorContains(t, cmd.Execute(WithArgs("-o", "jsonpath={.missing}"), WithReset(), AssertExitCode(t, 1)),
+			"cannot format result as jsonpath: cannot select field 'missing' of non-object")
+		require.ErrorContains(t, cmd.Execute(WithArgs("-o", "template"), WithReset(), AssertExitCode(t, 1)),
+			"cannot format result as template: template required")
+	})
+}
+
+func TestRunWithResult_single(t *testing.T) {
+	var runErr error
+	cmd := RunWithResult(New(), func(context.Context) (*outputTestItem, error) {
+		return &outputTestItem{Name: "only", Size: 2}, runErr
+	})
+	getStdout, _ := cmd.CaptureCobraOutput(t)
+	require.NoError(t, cmd.Execute(WithArgs(), AssertExitCode(t, 0)))
+	assert.Equal(t, "NAME   SIZE   TAGS\nonly   2      \n", getStdout())
+
+	runErr = errors.New("some error")
+	require.ErrorIs(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 1)), runErr)
+}
+
+func TestRunWithResult_customFormat(t *testing.T) {
+	cmd := RunWithResult(New(), func(context.Context) ([]string, error) {
+		return []string{"a", "b"}, nil
+	},
+		WithFormatter("count", func(w io.Writer, result any, options FormatOptions) error {
+			_, err := fmt.Fprintf(w, "%s%d\n", options.Arg, len(result.([]string)))
+			return err
+		}),
+		WithDefaultFormat("count"),
+	)
+	getStdout, _ := cmd.CaptureCobraOutput(t)
+	require.NoError(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 0)))
+	require.NoError(t, cmd.Execute(WithArgs("-o", "count=total "), WithReset(), AssertExitCode(t, 0)))
+	require.NoError(t, cmd.Execute(WithArgs("-o", "table"), WithReset(), AssertExitCode(t, 0)))
+	assert.Equal(t, "2\ntotal 2\nVALUE\na\nb\n", getStdout())
+
+	completions, _ := cmd.CaptureCompletions(t, "-o", "")
+	assert.Contains(t, completions, Completion{Value: "count"})
+
+	assert.PanicsWithValue(t, "unknown default format 'xml'", func() {
+		RunWithResult(New(), func(context.Context) (int, error) { return 0, nil }, WithDefaultFormat("xml"))
+	})
+}
+
+func TestRunWithResult_outputFlag(t *testing.T) {
+	run := func(context.Context) (string, error) { return "result", nil }
+	var output string
+	newCommand := func() Command {
+		return New().Flag(flag.String(&output, flag.NotEmpty), flag.RegisterOptions{Name: "output", Shorthand: "o"})
+	}
+
+	cmd := RunWithResult(newCommand(), run, WithOutputFlag("format", "f"))
+	getStdout, _ := cmd.CaptureCobraOutput(t)
+	require.NoError(t, cmd.Execute(WithArgs("-o", "file", "-f", "json"), AssertExitCode(t, 0)))
+	assert.Equal(t, "\"result\"\n", getStdout())
+	assert.Equal(t, "file", output)
+
+	cmd = RunWithResult(newCommand(), run, WithOutputFlag("format", "o"))
+	assert.Empty(t, cmd.Flags().Lookup("format").Shorthand)
+
+	assert.PanicsWithValue(t, "flag --output is registered already, see WithOutputFlag", func() {
+		RunWithResult(newCommand(), run)
+	})
+	assert.PanicsWithValue(t, "flag --no-headers is registered already, see WithOutputFlag", func() {
+		RunWithResult(New().Flag(flag.Bool(new(bool)), flag.RegisterOptions{Name: "no-headers"}), run)
+	})
+}
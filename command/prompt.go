@@ -0,0 +1,217 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// A Prompter asks interactively for the values of missing required flags, see WithPrompter.
+// Use NewPrompter to construct one.
+type Prompter struct {
+	reader *bufio.Reader
+	out    io.Writer
+	// readSecret reads a line without echoing the input, see [flag.RegisterOptions.Secret].
+	readSecret func() (string, error)
+}
+
+// NewPrompter constructs a Prompter reading the answers from in and writing the prompts to out.
+// Secrets are read like any other answer, as hiding the input requires a terminal, see WithPrompting.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	p := &Prompter{reader: bufio.NewReader(in), out: out}
+	p.readSecret = p.readLine
+	return p
+}
+
+// WithPrompting asks for the values of missing required flags on the terminal,
+// if the standard input is a terminal. Otherwise, missing required flags are reported as usual.
+// The prompts are written to the standard error, and the input of secrets is hidden using "stty".
+// Prompting for a secret fails if "stty" is not available.
+// See WithPrompter for details.
+func WithPrompting() ExecuteOption {
+	return applyToExecuteOptions(func(options *executeOptions) {
+		if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			prompter := NewPrompter(os.Stdin, os.Stderr)
+			prompter.readSecret = prompter.readLineWithoutEcho
+			options.Prompter = prompter
+		}
+	})
+}
+
+// WithPrompter asks for the values of missing required flags using the given Prompter.
+// Prompting happens after the flags have been bound, and flags having a value from any source,
+// see [flag.SourceOf], are not prompted for. Hidden flags are not prompted for either.
+// The kind of prompt depends on the flag:
+//
+//   - Flags with [flag.EnumValue] let the user select one of the allowed values.
+//   - Boolean flags ask for confirmation with y or n.
+//   - Flags registered with [flag.RegisterOptions.Secret] hide the input.
+//   - All other flags ask for text.
+//
+// Invalid answers are rejected by the flag value, the error is shown and the prompt is repeated.
+// Prompted flags count as changed and have the source [flag.SourcePrompt].
+func WithPrompter(prompter *Prompter) ExecuteOption {
+	return applyToExecuteOptions(func(options *executeOptions) {
+		options.Prompter = prompter
+	})
+}
+
+// errPrompt is returned if prompting fails, for example due to the end of input.
+var errPrompt = errors.New("cannot prompt for flag")
+
+// installPrompting prompts for missing required flags after the bindings have run,
+// but before the validation, see installValidation.
+// The returned function restores the previous state.
+func (c Command) installPrompting(prompter *Prompter) (restore func()) {
+	if prompter == nil {
+		return func() {}
+	}
+	var restores []func()
+	for command := range c.All() {
+		previous := command.PreRunE
+		command.PreRunE = chainCobraRun(previous, func(cmd *cobra.Command, _ []string) error {
+			return prompter.promptMissingRequiredFlags(cmd)
+		})
+		restores = append(restores, func() {
+			command.PreRunE = previous
+		})
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+func (p *Prompter) promptMissingRequiredFlags(cmd *cobra.Command) (err error) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		if err != nil || len(required) == 0 || required[0] != "true" || f.Hidden || flag.SourceOf(f) != flag.SourceDefault {
+			return
+		}
+		if err = p.prompt(f); err != nil {
+			err = fmt.Errorf("%w --%s: %w", errPrompt, f.Name, err)
+			return
+		}
+		f.Changed = true
+		flag.SetSource(f, flag.SourcePrompt)
+	})
+	return
+}
+
+// prompt repeats asking for the value of the given flag until the flag accepts it.
+func (p *Prompter) prompt(f *pflag.Flag) error {
+	value := f.Value
+	if collecting, ok := flag.ValueAs[collectingValue](value); ok {
+		// see WithAggregatedErrors, the errors are needed here for re-prompting
+		value = collecting.Unwrap()
+	}
+	label := f.Usage
+	if label == "" {
+		label = "--" + f.Name
+	}
+	for {
+		answer, err := p.ask(f, value, label)
+		if err != nil {
+			return err
+		}
+		if err := value.Set(answer); err != nil {
+			_, _ = fmt.Fprintf(p.out, "Invalid value: %v\n", err)
+			continue
+		}
+		return nil
+	}
+}
+
+// ask writes the prompt suitable for the given flag and returns the answer.
+func (p *Prompter) ask(f *pflag.Flag, value pflag.Value, label string) (string, error) {
+	if enumValue, ok := flag.ValueAs[flag.EnumValue](value); ok {
+		allowed := enumValue.EnumValues()
+		_, _ = fmt.Fprintf(p.out, "%s:\n", label)
+		for i, allowedValue := range allowed {
+			_, _ = fmt.Fprintf(p.out, "  %d) %s\n", i+1, allowedValue)
+		}
+		_, _ = fmt.Fprintf(p.out, "Select [1-%d]: ", len(allowed))
+		answer, err := p.readLine()
+		if index, convErr := strconv.Atoi(answer); convErr == nil && index >= 1 && index <= len(allowed) {
+			return allowed[index-1], err
+		}
+		return answer, err
+	}
+	if boolValue, ok := value.(interface{ IsBoolFlag() bool }); ok && boolValue.IsBoolFlag() {
+		_, _ = fmt.Fprintf(p.out, "%s [y/n]: ", label)
+		answer, err := p.readLine()
+		switch {
+		case slices.Contains([]string{"y", "yes"}, strings.ToLower(answer)):
+			return "true", err
+		case slices.Contains([]string{"n", "no"}, strings.ToLower(answer)):
+			return "false", err
+		default:
+			return answer, err
+		}
+	}
+	_, _ = fmt.Fprintf(p.out, "%s: ", label)
+	if isSecret(f) {
+		answer, err := p.readSecret()
+		_, _ = fmt.Fprintln(p.out)
+		return answer, err
+	}
+	return p.readLine()
+}
+
+// isSecret returns true if the flag has been registered with [flag.RegisterOptions.Secret].
+func isSecret(f *pflag.Flag) bool {
+	secret := f.Annotations[flag.SecretAnnotation]
+	return len(secret) > 0 && secret[0] == "true"
+}
+
+// readLine reads the next line without the line ending.
+// Returns the last line even if it does not end with a newline, and io.EOF afterwards.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err //nolint:wrapcheck
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// errHideInput is returned if the input of a secret cannot be hidden, see readLineWithoutEcho.
+var errHideInput = errors.New("cannot hide input")
+
+// readLineWithoutEcho disables the echo of the terminal using "stty" while reading the line.
+// Fails with errHideInput if "stty" is not available or fails, as the secret would be shown otherwise.
+func (p *Prompter) readLineWithoutEcho() (string, error) {
+	stty := func(arg string) error {
+		cmd := exec.Command("stty", arg)
+		cmd.Stdin = os.Stdin
+		return cmd.Run() //nolint:wrapcheck
+	}
+	if err := stty("-echo"); err != nil {
+		return "", fmt.Errorf("%w: %w", errHideInput, err)
+	}
+	defer func() {
+		_ = stty("echo")
+	}()
+	return p.readLine()
+}
+
+// confirm asks the given question and returns true if the answer is y or yes.
+// Any other answer declines, including an empty one.
+func (p *Prompter) confirm(question string) (bool, error) {
+	_, _ = fmt.Fprintf(p.out, "%s [y/N]: ", question)
+	answer, err := p.readLine()
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains([]string{"y", "yes"}, strings.ToLower(strings.TrimSpace(answer))), nil
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPrompter(t *testing.T) {
+	var (
+		name, format, password string
+		port                   int
+		force                  bool
+	)
+	cmd := New().Use("app").
+		Flag(flag.String(&name, flag.NotEmpty), flag.RegisterOptions{Name: "name", Usage: "Your name", Required: true}).
+		Flag(flag.String(&port, strconv.Atoi), flag.RegisterOptions{Name: "port", Required: true}).
+		Flag(flag.Enum(&format, "text", "json"), flag.RegisterOptions{Name: "format", Usage: "Output format", Required: true}).
+		Flag(flag.Bool(&force), flag.RegisterOptions{Name: "force", Usage: "Force it", Required: true}).
+		Flag(flag.Env{Value: flag.String(&password, flag.NotEmpty), EnvVar: "SOME_PROMPT_PASSWORD"},
+			flag.RegisterOptions{Name: "password", Usage: "Password", Required: true, Secret: true})
+
+	var sources map[string]flag.Source
+	cmd = cmd.Run(func() error {
+		sources = map[string]flag.Source{}
+		for flagName, target := range map[string]any{"name": &name, "port": &port, "password": &password} {
+			sources[flagName] = cmd.Lookup(target).Source()
+		}
+		return nil
+	})
+
+	t.Run("prompts for missing flags", func(t *testing.T) {
+		var out strings.Builder
+		input := "maybe\ny\n3\njson\n\nsome name\nsecret\nx\n8080"
+		require.NoError(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 0),
+			WithPrompter(NewPrompter(strings.NewReader(input), &out))))
+		assert.Equal(t, "some name", name)
+		assert.Equal(t, 8080, port)
+		assert.Equal(t, "json", format)
+		assert.True(t, force)
+		assert.Equal(t, "secret", password)
+		assert.Equal(t, map[string]flag.Source{
+			"name": flag.SourcePrompt, "port": flag.SourcePrompt, "password": flag.SourcePrompt,
+		}, sources)
+		assert.Equal(t, `Force it [y/n]: Invalid value: strconv.ParseBool: parsing "maybe": invalid syntax
+Force it [y/n]: Output format:
+  1) text
+  2) json
+Select [1-2]: Invalid value: cannot parse parameter: value '3' must be one of text, json
+Output format:
+  1) text
+  2) json
+Select [1-2]: Your name: Invalid value: cannot parse parameter: value '' is empty
+Your name: Password: 
+--port: Invalid value: strconv.Atoi: parsing "x": invalid syntax
+--port: `, out.String())
+	})
+
+	t.Run("flags set otherwise are not prompted", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, cmd.Execute(WithArgs("--name", "n", "--force", "--format", "text", "--password", "p"), WithReset(), AssertExitCode(t, 0),
+			WithPrompter(NewPrompter(strings.NewReader("1"), &out))))
+		assert.Equal(t, "--port: ", out.String())
+		assert.Equal(t, map[string]flag.Source{
+			"name": flag.SourceFlag, "port": flag.SourcePrompt, "password": flag.SourceFlag,
+		}, sources)
+	})
+
+	t.Run("end of input is usage error", func(t *testing.T) {
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 1),
+			WithPrompter(NewPrompter(strings.NewReader(""), &strings.Builder{}))),
+			"cannot prompt for flag --force: EOF")
+	})
+
+	t.Run("invalid answers are prompted again with aggregated errors", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, cmd.Execute(WithArgs("--name", "n", "--force", "--format", "text", "--password", "p"),
+			WithReset(), WithAggregatedErrors(), AssertExitCode(t, 0),
+			WithPrompter(NewPrompter(strings.NewReader("x\n1"), &out))))
+		assert.Equal(t, 1, port)
+		assert.Equal(t, "--port: Invalid value: strconv.Atoi: parsing \"x\": invalid syntax\n--port: ", out.String())
+	})
+
+	t.Run("secret fails without stty", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		prompter := NewPrompter(strings.NewReader("secret\n"), &strings.Builder{})
+		prompter.readSecret = prompter.readLineWithoutEcho
+		require.ErrorContains(t, cmd.Execute(WithArgs("--name", "n", "--force", "--format", "text", "--port", "1"),
+			WithReset(), AssertExitCode(t, 1), WithPrompter(prompter)),
+			"cannot prompt for flag --password: cannot hide input")
+		assert.Empty(t, password)
+	})
+
+	t.Run("without prompting", func(t *testing.T) {
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, cmd.Execute(WithArgs(), WithReset(), AssertExitCode(t, 1)),
+			"required flag(s)")
+	})
+}
+
+func TestWithPrompter_hiddenFlag(t *testing.T) {
+	var token string
+	cmd := New().
+		Flag(flag.String(&token, flag.NotEmpty), flag.RegisterOptions{Name: "token", Required: true, Hidden: true}).
+		Run(func() error { return nil })
+	cmd.CaptureCobraOutput(t) // avoid confusing test output
+	var out strings.Builder
+	require.ErrorContains(t, cmd.Execute(WithArgs(), AssertExitCode(t, 1),
+		WithPrompter(NewPrompter(strings.NewReader("some token\n"), &out))),
+		`required flag(s) "token" not set`)
+	assert.Empty(t, out.String())
+	assert.Empty(t, token)
+}
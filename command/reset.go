@@ -0,0 +1,80 @@
+package command
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/pflag"
+)
+
+// Reset restores the state of this command and all sub commands added via AddCommands before any execution.
+// Every target registered via Flag is set back to the value it had during registration,
+// the remaining flags, such as --help, are set back to their default value,
+// the Changed state and the Source of all flags are cleared and the arguments set via WithArgs are removed.
+// This makes it possible to execute the same command tree several times, for example in table-driven tests or a REPL.
+// See also WithReset.
+func (c Command) Reset() {
+	for command := range c.All() {
+		command.resetFlags()
+	}
+	c.SetArgs(nil)
+}
+
+// resetFlags resets the flags of this command only, see Reset.
+func (c Command) resetFlags() {
+	for _, resetter := range *c.resetters {
+		resetter()
+	}
+	resetFlag := func(f *pflag.Flag) {
+		if _, ok := f.Value.(flag.Value); !ok && f.Changed {
+			resetToDefValue(f)
+		}
+		f.Changed = false
+		delete(f.Annotations, flag.SourceAnnotation)
+	}
+	c.Flags().VisitAll(resetFlag)
+	c.PersistentFlags().VisitAll(resetFlag)
+}
+
+// addResetter remembers the current value of the given target pointer to be restored during Reset.
+func (c Command) addResetter(target any) {
+	value := reflect.ValueOf(target).Elem()
+	initial := cloneValue(value)
+	*c.resetters = append(*c.resetters, func() {
+		value.Set(cloneValue(initial))
+	})
+}
+
+// cloneValue copies the given value, including the elements of slices,
+// such that appending to a restored slice target does not modify the initial value.
+func cloneValue(value reflect.Value) reflect.Value {
+	result := reflect.New(value.Type()).Elem()
+	if value.Kind() == reflect.Slice && !value.IsNil() {
+		result.Set(reflect.AppendSlice(reflect.MakeSlice(value.Type(), 0, value.Len()), value))
+	} else {
+		result.Set(value)
+	}
+	return result
+}
+
+// resetToDefValue resets flags not registered via Flag, such as the --help flag added by Cobra.
+func resetToDefValue(f *pflag.Flag) {
+	if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+		values, err := csv.NewReader(strings.NewReader(strings.Trim(f.DefValue, "[]"))).Read()
+		if err != nil {
+			values = nil
+		}
+		_ = sliceValue.Replace(values)
+		return
+	}
+	_ = f.Value.Set(f.DefValue)
+}
+
+// WithReset calls Command.Reset before the command is executed, see there.
+func WithReset() ExecuteOption {
+	return applyToExecuteOptions(func(options *executeOptions) {
+		options.Reset = true
+	})
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Reset(t *testing.T) {
+	var (
+		someInt    = 42
+		someSlice  = []string{"a"}
+		someBool   bool
+		someNative []string
+		gotRun     bool
+	)
+	cmd := New().
+		Flag(flag.String(&someInt, strconv.Atoi), flag.RegisterOptions{Name: "some-int", Persistent: true}).
+		Flag(flag.Slice(&someSlice, flag.ParseSliceOf[string](flag.NotEmpty)), flag.RegisterOptions{Name: "some-slice"}).
+		Flag(flag.Bool(&someBool), flag.RegisterOptions{Name: "some-bool"}).
+		MarkFlagsOneRequired(&someInt, &someBool).
+		Run(func() error {
+			gotRun = true
+			return nil
+		})
+	cmd.Flags().StringSliceVar(&someNative, "some-native", []string{"x", "y"}, "")
+	cmd.CaptureCobraOutput(t) // avoid confusing test output
+
+	tests := []struct {
+		name      string
+		args      []string
+		exitCode  int
+		wantRun   bool
+		wantInt   int
+		wantSlice []string
+		wantBool  bool
+	}{
+		{"all flags", []string{"--some-int", "1", "--some-slice", "b,c", "--some-bool", "--some-native", "z"},
+			0, true, 1, []string{"b", "c"}, true},
+		{"help", []string{"--help"}, 0, false, 42, []string{"a"}, false},
+		{"one required flag", []string{"--some-bool"}, 0, true, 42, []string{"a"}, true},
+		{"no flags", []string{}, 1, false, 42, []string{"a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRun = false
+			_ = cmd.Execute(WithReset(), WithArgs(tt.args...), AssertExitCode(t, tt.exitCode))
+			assert.Equal(t, tt.wantRun, gotRun)
+			assert.Equal(t, tt.wantInt, someInt)
+			assert.Equal(t, tt.wantSlice, someSlice)
+			assert.Equal(t, tt.wantBool, someBool)
+		})
+	}
+
+	t.Run("reset restores everything", func(t *testing.T) {
+		require.NoError(t, cmd.Execute(WithArgs("--some-int", "1", "--some-slice", "b", "--some-native", "z"),
+			AssertExitCode(t, 0)))
+		someSlice[0] = "modified"
+		cmd.Reset()
+		assert.Equal(t, 42, someInt)
+		assert.Equal(t, []string{"a"}, someSlice)
+		assert.Equal(t, []string{"x", "y"}, someNative)
+		assert.False(t, cmd.Flags().Changed("some-int"))
+		assert.False(t, cmd.Flags().Changed("some-native"))
+	})
+}
@@ -3,6 +3,8 @@ package command
 import (
 	"bytes"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -69,3 +71,38 @@ func (c Command) CaptureCobraOutput(t *testing.T) (getStdout, getStderr func() s
 	})
 	return stdout.String, stderr.String
 }
+
+// Completion is a shell completion candidate, see CaptureCompletions.
+type Completion struct {
+	Value string
+	// Description is empty if the candidate has no description.
+	Description string
+}
+
+// CaptureCompletions executes Cobra's hidden completion command in-process, as the shell would do,
+// and returns the completion candidates and the directive for the shell.
+// The last argument is the partial input to be completed, use "" to complete a new argument or flag value.
+// Resets the command before execution, see WithReset.
+//
+// This function is only useful for testing.
+func (c Command) CaptureCompletions(t *testing.T, args ...string) (completions []Completion, directive CompletionDirective) {
+	t.Helper()
+	getStdout, _ := c.CaptureCobraOutput(t)
+	if !assert.NoError(t, c.Execute(WithArgs(append([]string{completionRequestCmd}, args...)...),
+		WithReset(), AssertExitCode(t, 0))) {
+		return nil, completionDirectiveError
+	}
+	lines := strings.Split(strings.TrimSuffix(getStdout(), "\n"), "\n")
+	// the last line holds the directive, such as ":4"
+	directiveLine := lines[len(lines)-1]
+	parsedDirective, err := strconv.Atoi(strings.TrimPrefix(directiveLine, ":"))
+	if !strings.HasPrefix(directiveLine, ":") || err != nil {
+		assert.Failf(t, "invalid completion output", "output must end with directive, but got %q", directiveLine)
+		return nil, completionDirectiveError
+	}
+	for _, line := range lines[:len(lines)-1] {
+		value, description, _ := strings.Cut(line, "\t")
+		completions = append(completions, Completion{value, description})
+	}
+	return completions, CompletionDirective(parsedDirective)
+}
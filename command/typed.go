@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+)
+
+// Typed is a Command whose flags are registered against the fields of an options struct, see NewTyped.
+type Typed[Opts any] struct {
+	Command
+
+	opts *Opts
+}
+
+// NewTyped constructs a command with one flag per field of the options struct Opts,
+// see [flag.FromStruct] for the supported struct tags, including default values.
+// Use Typed.Run to receive the populated options.
+// The flags of the command are reset before each Execute, so the command can be executed repeatedly.
+// Panics if the struct is not supported.
+func NewTyped[Opts any](options ...flag.StructOption) Typed[Opts] {
+	opts := new(Opts)
+	cmd := New().FlagsFrom(opts, options...)
+	cmd.resetOnExecute = true
+	return Typed[Opts]{cmd, opts}
+}
+
+// Opts returns the pointer to the options struct the flags are registered against.
+// Use it to refer to flags, for example with MarkFlagsMutuallyExclusive(&cmd.Opts().SomeField, ...).
+func (t Typed[Opts]) Opts() *Opts {
+	return t.opts
+}
+
+// Run sets the given code to run during Execute, see Command.Run.
+// It receives the context of the command and a copy of the options populated from the flags.
+func (t Typed[Opts]) Run(run func(ctx context.Context, opts Opts) error) Command {
+	t.RunE = func(cmd *cobra.Command, _ []string) error {
+		if err := run(cmd.Context(), *t.opts); err != nil {
+			return fromRunCallbackError{err}
+		}
+		return nil
+	}
+	return t.Command
+}
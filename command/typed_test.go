@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTyped(t *testing.T) {
+	type options struct {
+		Name    string   `usage:"The name" required:"true"`
+		Count   int      `default:"3"`
+		Verbose bool     `short:"v"`
+		Tags    []string `default:"a,b"`
+	}
+	var got []options
+	cmd := NewTyped[options]()
+	cmd.MarkFlagsMutuallyExclusive(&cmd.Opts().Verbose, &cmd.Opts().Count)
+	root := New().Use("app").AddCommands(cmd.Run(func(ctx context.Context, opts options) error {
+		assert.NotNil(t, ctx)
+		got = append(got, opts)
+		if opts.Name == "fail" {
+			return errors.New("some error")
+		}
+		return nil
+	}).Use("typed"))
+	root.CaptureCobraOutput(t) // avoid confusing test output
+
+	t.Run("defaults are shown in usage", func(t *testing.T) {
+		assert.Equal(t, "3", cmd.Flags().Lookup("count").DefValue)
+		assert.Equal(t, "a,b", cmd.Flags().Lookup("tags").DefValue)
+	})
+
+	t.Run("repeated execution", func(t *testing.T) {
+		require.NoError(t, root.Execute(WithArgs("typed", "--name", "first", "--count", "5", "--tags", "c"),
+			AssertExitCode(t, 0)))
+		require.NoError(t, root.Execute(WithArgs("typed", "--name", "second", "-v"), AssertExitCode(t, 0)))
+		assert.Equal(t, []options{
+			{Name: "first", Count: 5, Tags: []string{"c"}},
+			{Name: "second", Count: 3, Verbose: true, Tags: []string{"a", "b"}},
+		}, got)
+	})
+
+	t.Run("required flag is checked again", func(t *testing.T) {
+		require.ErrorContains(t, root.Execute(WithArgs("typed"), AssertExitCode(t, 1)),
+			`required flag(s) "name" not set`)
+	})
+
+	t.Run("run error", func(t *testing.T) {
+		err := root.Execute(WithArgs("typed", "--name", "fail"), AssertExitCode(t, 1), WithErrorLogger(func(error) {}))
+		require.EqualError(t, err, "some error")
+		var errFromRun fromRunCallbackError
+		require.ErrorAs(t, err, &errFromRun)
+	})
+}
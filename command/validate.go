@@ -0,0 +1,97 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+)
+
+// Validate adds the given validator, which is run during Execute after all flags have been parsed and bound,
+// but before the Run callback of this command.
+// Use it for rules spanning several flags, such as "--max must not be smaller than --min".
+// All validators of the command are run and all returned errors are reported together as a usage error.
+func (c Command) Validate(validator func() error) Command {
+	*c.validators = append(*c.validators, validator)
+	return c
+}
+
+// RequiredIf marks the flag registered for the given target pointer (see Flag) as required
+// if the given predicate returns true during validation.
+// The flag counts as present if its value has a source other than the default,
+// such as an environment variable (see [flag.SourceOf]).
+// See also Validate.
+func (c Command) RequiredIf(target any, predicate func() bool) Command {
+	flagNames := c.getFlagNames([]any{target})
+	return c.Validate(func() error {
+		if !predicate() {
+			return nil
+		}
+		for _, flagName := range flagNames {
+			if f := c.Command.Flag(flagName); f != nil && flag.SourceOf(f) != flag.SourceDefault {
+				return nil
+			}
+		}
+		return fmt.Errorf("flag %s is required", quoteFlagNames(flagNames, " or "))
+	})
+}
+
+// validate runs after PreRunE, see installValidation.
+// Runs the same validation as Cobra does before Run and then all validators.
+// If the collector is not nil, all errors are reported together, see WithAggregatedErrors.
+func (c Command) validate(cmd *cobra.Command, collector *errorCollector) error {
+	var errs []error
+	if collector != nil {
+		collector.addMissingRequiredFlags(cmd)
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			collector.add(err)
+		}
+		collector.reported = true
+		errs = collector.errs
+	} else {
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err //nolint:wrapcheck
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+	for _, validator := range *c.validators {
+		if err := validator(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// installValidation installs validate for this command and all sub commands.
+// Validation needs to run after the flag bindings, which are added to PreRunE during Flag,
+// so this happens just before the command is executed.
+// The returned function restores the previous state.
+func (c Command) installValidation(collector *errorCollector) (restore func()) {
+	var restores []func()
+	for command := range c.All() {
+		previous := command.PreRunE
+		command.PreRunE = chainCobraRun(previous, func(cmd *cobra.Command, _ []string) error {
+			return command.validate(cmd, collector)
+		})
+		restores = append(restores, func() {
+			command.PreRunE = previous
+		})
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+func quoteFlagNames(flagNames []string, sep string) string {
+	quoted := make([]string, 0, len(flagNames))
+	for _, flagName := range flagNames {
+		quoted = append(quoted, fmt.Sprintf("%q", flagName))
+	}
+	return strings.Join(quoted, sep)
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Validate(t *testing.T) {
+	var (
+		minimum, maximum int
+		validatorRuns    int
+	)
+	cmd := New().
+		Flag(flag.String(&minimum, strconv.Atoi), flag.RegisterOptions{Name: "min"}).
+		Flag(flag.String(&maximum, strconv.Atoi), flag.RegisterOptions{Name: "max"}).
+		Validate(func() error {
+			validatorRuns++
+			if maximum < minimum {
+				return errors.New("--max must not be smaller than --min")
+			}
+			return nil
+		}).
+		Validate(func() error {
+			if minimum < 0 {
+				return errors.New("--min must not be negative")
+			}
+			return nil
+		}).
+		Run(func() error {
+			// some dummy to make Cobra actually parse flags
+			return nil
+		})
+
+	t.Run("valid flags", func(t *testing.T) {
+		require.NoError(t, cmd.Execute(WithArgs("--min", "1", "--max", "2"),
+			AssertExitCode(t, 0),
+			AssertWithRun(t, nil),
+		))
+		assert.Equal(t, 1, validatorRuns)
+	})
+
+	t.Run("all violations are reported", func(t *testing.T) {
+		getStdout, getStderr := cmd.CaptureCobraOutput(t)
+		err := cmd.Execute(WithArgs("--min", "-2", "--max", "-3"), AssertExitCode(t, 1))
+		require.EqualError(t, err, "--max must not be smaller than --min\n--min must not be negative")
+		assert.Contains(t, getStderr(), "Error: --max must not be smaller than --min\n--min must not be negative")
+		assert.Contains(t, getStdout(), "Usage:")
+	})
+
+	t.Run("required flags are checked before", func(t *testing.T) {
+		var (
+			someVal string
+		)
+		cmd := New().
+			Flag(flag.String(&someVal, flag.NotEmpty), flag.RegisterOptions{Name: "some-val", Required: true}).
+			Validate(func() error {
+				t.Fatal("should never be called")
+				return nil
+			}).
+			Run(func() error {
+				return nil
+			})
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, cmd.Execute(WithArgs(), AssertExitCode(t, 1)),
+			`required flag(s) "some-val" not set`)
+	})
+
+	t.Run("pre-run state is restored", func(t *testing.T) {
+		assert.Nil(t, cmd.PreRunE)
+	})
+}
+
+func TestCommand_RequiredIf(t *testing.T) {
+	var (
+		useTLS          bool
+		tlsCert, tlsKey string
+	)
+	cmd := New().
+		Flag(flag.Bool(&useTLS), flag.RegisterOptions{Name: "tls"}).
+		Flag(flag.String(&tlsCert, flag.NotEmpty), flag.RegisterOptions{Name: "tls-cert"}).
+		Flag(flag.String(&tlsKey, flag.NotEmpty), flag.RegisterOptions{Name: "tls-key"}).
+		Flag(flag.Env{Value: flag.String(&tlsKey, flag.NotEmpty), EnvVar: "SOME_REQUIRED_IF_TLS_KEY"},
+			flag.RegisterOptions{Name: "tls-private-key"}).
+		RequiredIf(&tlsCert, func() bool {
+			return useTLS
+		}).
+		RequiredIf(&tlsKey, func() bool {
+			return tlsCert != ""
+		}).
+		Run(func() error {
+			// some dummy to make Cobra actually parse flags
+			return nil
+		})
+	cmd.CaptureCobraOutput(t) // avoid confusing test output
+
+	t.Run("predicates false", func(t *testing.T) {
+		require.NoError(t, cmd.Execute(WithArgs(), AssertExitCode(t, 0)))
+	})
+
+	t.Run("predicate true, but flag missing", func(t *testing.T) {
+		require.EqualError(t, cmd.Execute(WithArgs("--tls"), AssertExitCode(t, 1)),
+			`flag "tls-cert" is required`)
+	})
+
+	t.Run("one flag missing", func(t *testing.T) {
+		require.EqualError(t, cmd.Execute(WithArgs("--tls", "--tls-cert", "cert.pem"), AssertExitCode(t, 1)),
+			`flag "tls-key" or "tls-private-key" is required`)
+	})
+
+	t.Run("all flags present", func(t *testing.T) {
+		require.NoError(t, cmd.Execute(WithArgs("--tls", "--tls-cert", "cert.pem", "--tls-private-key", "key.pem"),
+			AssertExitCode(t, 0)))
+	})
+
+	t.Run("flag from environment variable", func(t *testing.T) {
+		t.Setenv("SOME_REQUIRED_IF_TLS_KEY", "key.pem")
+		require.NoError(t, cmd.Execute(WithArgs("--tls", "--tls-cert", "cert.pem"), WithReset(), AssertExitCode(t, 0)))
+		assert.Equal(t, "key.pem", tlsKey)
+	})
+
+	t.Run("panics with unknown target", func(t *testing.T) {
+		var (
+			someVal string
+		)
+		assert.Panics(t, func() {
+			New().RequiredIf(&someVal, func() bool {
+				return true
+			})
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"text/tabwriter"
+
+	"github.com/neiser/go-nagini/flag"
+)
+
+// These variables override the version information read from the build info, see Command.Version.
+// Set them using ldflags, for example
+//
+//	go build -ldflags "-X github.com/neiser/go-nagini/command.buildVersion=v1.2.3"
+//
+//nolint:gochecknoglobals
+var (
+	buildVersion  string
+	buildRevision string
+	buildTime     string
+	readBuildInfo = debug.ReadBuildInfo
+)
+
+// VersionInfo describes the version of the application, see Command.Version.
+type VersionInfo struct {
+	// Version is the module version, or the one set via ldflags.
+	Version string `json:"version"`
+	// Revision is the VCS revision, usually a Git commit hash.
+	Revision string `json:"revision,omitempty"`
+	// Dirty is true if the VCS working tree had local modifications during build.
+	Dirty bool `json:"dirty,omitempty"`
+	// BuildTime is the time of the VCS revision, or the one set via ldflags.
+	BuildTime string `json:"buildTime,omitempty"`
+	// GoVersion is the Go version used to build the application.
+	GoVersion string `json:"goVersion,omitempty"`
+}
+
+// VersionOptions are used by Command.Version.
+type VersionOptions struct {
+	// Override replaces the non-empty fields of the VersionInfo read from the build info.
+	// Note that overrides set via ldflags take precedence.
+	Override VersionInfo
+	// Command adds a "version" sub command printing the VersionInfo as text or JSON (--output json).
+	Command bool
+	// NoFlag does not populate the Version of the cobra.Command,
+	// which disables Cobra's --version flag printing only the short version.
+	NoFlag bool
+}
+
+// Version populates the version of the command from [debug.ReadBuildInfo],
+// which enables Cobra's --version flag.
+// Optionally adds a "version" sub command, see VersionOptions.
+func (c Command) Version(options VersionOptions) Command {
+	info := readVersionInfo().override(options.Override).override(VersionInfo{
+		Version:   buildVersion,
+		Revision:  buildRevision,
+		BuildTime: buildTime,
+	})
+	if !options.NoFlag {
+		c.Command.Version = info.Version
+	}
+	if options.Command {
+		c.AddCommands(newVersionCommand(info))
+	}
+	return c
+}
+
+func readVersionInfo() (info VersionInfo) {
+	buildInfo, ok := readBuildInfo()
+	if !ok {
+		return
+	}
+	info.Version = buildInfo.Main.Version
+	info.GoVersion = buildInfo.GoVersion
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		}
+	}
+	return
+}
+
+func (i VersionInfo) override(other VersionInfo) VersionInfo {
+	if other.Version != "" {
+		i.Version = other.Version
+	}
+	if other.Revision != "" {
+		i.Revision = other.Revision
+	}
+	if other.Dirty {
+		i.Dirty = true
+	}
+	if other.BuildTime != "" {
+		i.BuildTime = other.BuildTime
+	}
+	if other.GoVersion != "" {
+		i.GoVersion = other.GoVersion
+	}
+	return i
+}
+
+// versionOutput is the --output flag of the version command.
+type versionOutput string
+
+const (
+	versionOutputText versionOutput = "text"
+	versionOutputJSON versionOutput = "json"
+)
+
+func newVersionCommand(info VersionInfo) Command {
+	output := versionOutputText
+	cmd := New().
+		Use("version").
+		Short("Print the version information").
+		Flag(flag.Enum(&output, versionOutputText, versionOutputJSON), flag.RegisterOptions{
+			Name:      "output",
+			Shorthand: "o",
+			Usage:     "Output format, either text or json",
+		})
+	return cmd.Run(func() error {
+		if output == versionOutputJSON {
+			return info.writeJSON(cmd.OutOrStdout())
+		}
+		return info.writeText(cmd.OutOrStdout())
+	})
+}
+
+//nolint:wrapcheck
+func (i VersionInfo) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(i)
+}
+
+//nolint:wrapcheck
+func (i VersionInfo) writeText(w io.Writer) error {
+	tabWriter := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintf(tabWriter, "Version:\t%s\n", i.Version)
+	if i.Revision != "" {
+		_, _ = fmt.Fprintf(tabWriter, "Revision:\t%s\n", i.Revision)
+		_, _ = fmt.Fprintf(tabWriter, "Dirty:\t%t\n", i.Dirty)
+	}
+	if i.BuildTime != "" {
+		_, _ = fmt.Fprintf(tabWriter, "Build time:\t%s\n", i.BuildTime)
+	}
+	if i.GoVersion != "" {
+		_, _ = fmt.Fprintf(tabWriter, "Go version:\t%s\n", i.GoVersion)
+	}
+	return tabWriter.Flush()
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Version(t *testing.T) {
+	previousReadBuildInfo := readBuildInfo
+	t.Cleanup(func() {
+		readBuildInfo = previousReadBuildInfo
+	})
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.2.3",
+			Main:      debug.Module{Version: "v4.5.6"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abcdef"},
+				{Key: "vcs.modified", Value: "true"},
+				{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+			},
+		}, true
+	}
+
+	t.Run("version flag", func(t *testing.T) {
+		cmd := New().Use("app").Version(VersionOptions{})
+		getStdout, _ := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--version"), AssertExitCode(t, 0)))
+		assert.Equal(t, "app version v4.5.6\n", getStdout())
+	})
+
+	t.Run("version flag with override", func(t *testing.T) {
+		cmd := New().Use("app").Version(VersionOptions{Override: VersionInfo{Version: "v7.8.9"}})
+		getStdout, _ := cmd.CaptureCobraOutput(t)
+		require.NoError(t, cmd.Execute(WithArgs("--version"), AssertExitCode(t, 0)))
+		assert.Equal(t, "app version v7.8.9\n", getStdout())
+	})
+
+	t.Run("version flag overridden by ldflags", func(t *testing.T) {
+		buildVersion = "v0.0.1"
+		t.Cleanup(func() {
+			buildVersion = ""
+		})
+		cmd := New().Use("app").Version(VersionOptions{Override: VersionInfo{Version: "v7.8.9"}})
+		assert.Equal(t, "v0.0.1", cmd.Command.Version)
+	})
+
+	t.Run("no version flag", func(t *testing.T) {
+		cmd := New().Use("app").Version(VersionOptions{NoFlag: true, Command: true})
+		cmd.CaptureCobraOutput(t) // avoid confusing test output
+		require.ErrorContains(t, cmd.Execute(WithArgs("--version"), AssertExitCode(t, 1)),
+			"unknown flag: --version")
+	})
+
+	t.Run("version command", func(t *testing.T) {
+		cmd := New().Use("app").Version(VersionOptions{Command: true})
+
+		t.Run("text output", func(t *testing.T) {
+			getStdout, _ := cmd.CaptureCobraOutput(t)
+			require.NoError(t, cmd.Execute(WithArgs("version"), AssertExitCode(t, 0)))
+			assert.Equal(t, `Version:    v4.5.6
+Revision:   abcdef
+Dirty:      true
+Build time: 2025-01-02T03:04:05Z
+Go version: go1.2.3
+`, getStdout())
+		})
+
+		t.Run("json output", func(t *testing.T) {
+			getStdout, _ := cmd.CaptureCobraOutput(t)
+			require.NoError(t, cmd.Execute(WithArgs("version", "-o", "json"), AssertExitCode(t, 0)))
+			assert.JSONEq(t, `{
+  "version": "v4.5.6",
+  "revision": "abcdef",
+  "dirty": true,
+  "buildTime": "2025-01-02T03:04:05Z",
+  "goVersion": "go1.2.3"
+}`, getStdout())
+		})
+
+		t.Run("invalid output", func(t *testing.T) {
+			cmd.CaptureCobraOutput(t) // avoid confusing test output
+			require.ErrorContains(t, cmd.Execute(WithArgs("version", "-o", "yaml"), AssertExitCode(t, 1)),
+				"cannot parse parameter: value 'yaml' must be one of text, json")
+		})
+	})
+}
@@ -0,0 +1,2 @@
+// Package doc generates documentation, such as man pages or Markdown reference pages, from a command.Command tree.
+package doc
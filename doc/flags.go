@@ -0,0 +1,106 @@
+package doc
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagInfo describes a registered flag for documentation.
+type flagInfo struct {
+	Name       string
+	Shorthand  string
+	Type       string
+	Usage      string
+	Default    string
+	Required   bool
+	Slice      bool
+	EnumValues []string
+	Deprecated string
+	Binding    flag.BindingInfo
+}
+
+// collectFlags returns all non-hidden flags, sorted by name.
+// Deprecated flags are included, as opposed to the usage help output.
+func collectFlags(flags *pflag.FlagSet) (result []flagInfo) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		typeName, usage := pflag.UnquoteUsage(f)
+		info := flagInfo{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       typeName,
+			Usage:      usage,
+			Deprecated: f.Deprecated,
+		}
+		if !isZeroDefault(f.DefValue) {
+			info.Default = f.DefValue
+		}
+		if annotation, found := f.Annotations[cobra.BashCompOneRequiredFlag]; found && len(annotation) > 0 {
+			info.Required = annotation[0] == "true"
+		}
+		if _, ok := flag.ValueAs[pflag.SliceValue](f.Value); ok {
+			info.Slice = true
+		}
+		if enumValue, ok := flag.ValueAs[flag.EnumValue](f.Value); ok {
+			info.EnumValues = enumValue.EnumValues()
+		}
+		if describedBinding, ok := flag.ValueAs[flag.DescribedBinding](f.Value); ok {
+			info.Binding = describedBinding.DescribeBinding()
+		}
+		result = append(result, info)
+	})
+	return
+}
+
+func isZeroDefault(defValue string) bool {
+	switch defValue {
+	case "", "false", "0", "<nil>", "<empty>", "[]":
+		return true
+	default:
+		return false
+	}
+}
+
+// isDocumented returns false if the command or any of its parents is hidden or deprecated.
+func isDocumented(cmd command.Command) bool {
+	for parent := range cmd.Parents() {
+		if parent.Hidden || parent.Deprecated != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// documentedChildren returns the direct sub commands which are documented, see isDocumented.
+func documentedChildren(cmd command.Command) (result []command.Command) {
+	for child := range cmd.All() {
+		if child.Command == cmd.Command || child.Parent() != cmd.Command || !isDocumented(child) {
+			continue
+		}
+		result = append(result, child)
+	}
+	return
+}
+
+// parentCommand returns the parent of the given command, if any.
+func parentCommand(cmd command.Command) (command.Command, bool) {
+	for parent := range cmd.Parents() {
+		if parent.Command != cmd.Command {
+			return parent, true
+		}
+	}
+	return command.Command{}, false
+}
+
+// baseName returns the command path with spaces replaced by dashes,
+// which is used as the file name for the generated documentation.
+func baseName(cmd *cobra.Command) string {
+	return filepath.Clean(strings.ReplaceAll(cmd.CommandPath(), " ", "-"))
+}
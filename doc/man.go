@@ -0,0 +1,223 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+)
+
+// ManOptions are used when generating man pages, see WriteMan and GenerateManTree.
+type ManOptions struct {
+	// Section is the manual section, defaults to "1" (user commands).
+	Section string
+	// Date is shown in the footer of the man page. Leave empty to keep the output deterministic.
+	Date string
+	// Source is shown in the footer of the man page, usually the project name and version.
+	Source string
+	// Manual is shown in the header of the man page.
+	Manual string
+}
+
+func (o ManOptions) section() string {
+	if o.Section == "" {
+		return "1"
+	}
+	return o.Section
+}
+
+// GenerateManTree writes one man page for the given command and each documented sub command into dir.
+// Hidden or deprecated commands are skipped.
+// The file names are the command paths joined with dashes, for example "app-sub.1".
+func GenerateManTree(root command.Command, dir string, options ManOptions) error {
+	for cmd := range root.All() {
+		if !isDocumented(cmd) {
+			continue
+		}
+		var buffer bytes.Buffer
+		if err := WriteMan(&buffer, cmd, options); err != nil {
+			return err
+		}
+		fileName := filepath.Join(dir, baseName(cmd.Command)+"."+options.section())
+		if err := os.WriteFile(fileName, buffer.Bytes(), 0o644); err != nil { //nolint:gosec,mnd
+			return fmt.Errorf("cannot write man page: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteMan writes the man page in roff format for the given command.
+// Besides the usual sections, the options section contains the metadata of the flags,
+// such as allowed values, required markers, bindings and deprecations.
+//
+//nolint:wrapcheck
+func WriteMan(w io.Writer, cmd command.Command, options ManOptions) error {
+	// collect flags first, as this also merges the persistent flags needed for cobra.Command.UseLine
+	localFlags, inheritedFlags := collectFlags(cmd.LocalFlags()), collectFlags(cmd.InheritedFlags())
+	m := manWriter{}
+	title := strings.ToUpper(baseName(cmd.Command))
+	m.printf(".TH %s %s %s %s %s\n", roffQuote(title), roffQuote(options.section()),
+		roffQuote(options.Date), roffQuote(options.Source), roffQuote(options.Manual))
+
+	m.section("NAME")
+	m.printf("%s \\- %s\n", roffEscape(baseName(cmd.Command)), roffEscape(cmd.Command.Short))
+
+	m.section("SYNOPSIS")
+	m.printf(".B %s\n", roffEscape(cmd.UseLine()))
+
+	if description := cmd.Command.Long; description != "" || cmd.Command.Short != "" {
+		if description == "" {
+			description = cmd.Command.Short
+		}
+		m.section("DESCRIPTION")
+		m.paragraphs(description)
+	}
+
+	if len(localFlags) > 0 {
+		m.section("OPTIONS")
+		m.flags(localFlags)
+	}
+	if len(inheritedFlags) > 0 {
+		m.section("OPTIONS INHERITED FROM PARENT COMMANDS")
+		m.flags(inheritedFlags)
+	}
+
+	m.section("EXIT STATUS")
+	m.printf(".TP\n.B 0\nSuccessful execution.\n")
+	m.printf(".TP\n.B 1\nInvalid usage, such as invalid flag values, or failed execution.\n")
+	m.printf(".PP\nOther exit codes may be returned by the command itself.\n")
+
+	var seeAlso []string
+	if parent, ok := parentCommand(cmd); ok {
+		seeAlso = append(seeAlso, manReference(parent, options))
+	}
+	for _, child := range documentedChildren(cmd) {
+		seeAlso = append(seeAlso, manReference(child, options))
+	}
+	if len(seeAlso) > 0 {
+		m.section("SEE ALSO")
+		m.printf("%s\n", strings.Join(seeAlso, ", "))
+	}
+
+	_, err := w.Write(m.Bytes())
+	return err
+}
+
+type manWriter struct {
+	bytes.Buffer
+}
+
+func (m *manWriter) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(m, format, args...)
+}
+
+func (m *manWriter) section(name string) {
+	m.printf(".SH %s\n", name)
+}
+
+func (m *manWriter) paragraphs(text string) {
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			m.printf(".PP\n")
+		}
+		for _, line := range strings.Split(paragraph, "\n") {
+			m.printf("%s\n", roffEscape(line))
+		}
+	}
+}
+
+func (m *manWriter) flags(flags []flagInfo) {
+	for _, f := range flags {
+		m.printf(".TP\n")
+		var names []string
+		if f.Shorthand != "" {
+			names = append(names, `\fB\-`+roffEscape(f.Shorthand)+`\fR`)
+		}
+		longName := `\fB\-\-` + roffEscape(f.Name) + `\fR`
+		if f.Type != "" {
+			longName += `=\fI` + roffEscape(f.Type) + `\fR`
+		}
+		m.printf("%s\n", strings.Join(append(names, longName), ", "))
+		if f.Usage != "" {
+			m.printf("%s\n", roffEscape(f.Usage))
+		}
+		for _, line := range flagMetadata(f) {
+			m.printf(".br\n%s\n", roffEscape(line))
+		}
+	}
+}
+
+// flagMetadata returns the nagini-specific information about a flag as separate lines.
+func flagMetadata(f flagInfo) (lines []string) {
+	if f.Required {
+		lines = append(lines, "Required.")
+	}
+	if f.Default != "" {
+		lines = append(lines, fmt.Sprintf("Default: %s", f.Default))
+	}
+	if len(f.EnumValues) > 0 {
+		lines = append(lines, fmt.Sprintf("Allowed values: %s", strings.Join(f.EnumValues, ", ")))
+	}
+	if f.Slice {
+		lines = append(lines, "Multiple values can be given comma-separated.")
+	}
+	if f.Binding.EnvVar != "" {
+		lines = append(lines, fmt.Sprintf("Environment variable: %s", f.Binding.EnvVar))
+	}
+	if f.Binding.ConfigKey != "" {
+		lines = append(lines, fmt.Sprintf("Config key: %s", f.Binding.ConfigKey))
+	}
+	if f.Deprecated != "" {
+		lines = append(lines, fmt.Sprintf("Deprecated: %s", f.Deprecated))
+	}
+	return
+}
+
+func manReference(cmd command.Command, options ManOptions) string {
+	return fmt.Sprintf(`\fB%s\fP(%s)`, roffEscape(baseName(cmd.Command)), options.section())
+}
+
+// roffEscape escapes text such that roff does not interpret it.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
+
+// NewManCommand constructs a hidden "gen-man" command, which generates the man pages
+// for the whole command tree it is added to via [command.Command.AddCommands].
+// The man pages are written into the directory given by the --dir flag.
+func NewManCommand(options ManOptions) command.Command {
+	dir := "."
+	cmd := command.New().
+		Use("gen-man").
+		Short("Generate man pages").
+		Flag(flag.String(&dir, flag.NotEmptyTrimmed), flag.RegisterOptions{
+			Name:  "dir",
+			Usage: "Directory to write the man pages into",
+		})
+	cmd.Hidden = true
+	return cmd.Run(func() error {
+		return GenerateManTree(rootCommand(cmd), dir, options)
+	})
+}
+
+// rootCommand returns the root of the command tree the given command has been added to.
+func rootCommand(cmd command.Command) (root command.Command) {
+	for parent := range cmd.Parents() {
+		root = parent
+	}
+	return
+}
@@ -0,0 +1,170 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/neiser/go-nagini/flag"
+	"github.com/neiser/go-nagini/flag/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type someOutput string
+
+func newSomeCommand() command.Command {
+	var (
+		someName   string
+		someInts   []int
+		someOutput someOutput = "text"
+		someBool   bool
+		someOld    string
+	)
+	return command.New().
+		Use("app").
+		Short("Some app").
+		Long("Does something.").
+		LongParagraph("Really.").
+		Flag(flag.Bool(&someBool), flag.RegisterOptions{
+			Name:       "verbose",
+			Shorthand:  "v",
+			Usage:      "Be verbose",
+			Persistent: true,
+		}).
+		AddCommands(
+			command.New().
+				Use("sub").
+				Short("Some sub command").
+				Flag(flag.Env{Value: binding.Viper{Value: flag.String(&someName, flag.NotEmpty), ConfigKey: "some.name"}, EnvVar: "SOME_NAME"}, flag.RegisterOptions{
+					Name:     "name",
+					Usage:    "Some `name` to use",
+					Required: true,
+				}).
+				Flag(flag.Slice(&someInts, flag.ParseSliceOf(strconv.Atoi)), flag.RegisterOptions{
+					Name: "ints",
+				}).
+				Flag(flag.Enum(&someOutput, "text", "json"), flag.RegisterOptions{
+					Name:      "output",
+					Shorthand: "o",
+				}).
+				Flag(flag.String(&someOld, flag.NotEmpty), flag.RegisterOptions{
+					Name:       "old",
+					Deprecated: "use --name instead",
+				}).
+				Run(func() error {
+					return nil
+				}),
+			NewManCommand(ManOptions{}),
+		)
+}
+
+func TestWriteMan(t *testing.T) {
+	cmd := newSomeCommand()
+
+	t.Run("root command", func(t *testing.T) {
+		var buffer bytes.Buffer
+		require.NoError(t, WriteMan(&buffer, cmd, ManOptions{Source: "app v1.0.0"}))
+		assert.Equal(t, `.TH "APP" "1" "" "app v1.0.0" ""
+.SH NAME
+app \- Some app
+.SH SYNOPSIS
+.B app [flags]
+.SH DESCRIPTION
+Does something.
+.PP
+Really.
+.SH OPTIONS
+.TP
+\fB\-v\fR, \fB\-\-verbose\fR
+Be verbose
+.SH EXIT STATUS
+.TP
+.B 0
+Successful execution.
+.TP
+.B 1
+Invalid usage, such as invalid flag values, or failed execution.
+.PP
+Other exit codes may be returned by the command itself.
+.SH SEE ALSO
+\fBapp\-sub\fP(1)
+`, buffer.String())
+	})
+
+	t.Run("sub command", func(t *testing.T) {
+		var sub command.Command
+		for c := range cmd.All() {
+			if c.Name() == "sub" {
+				sub = c
+			}
+		}
+		var buffer bytes.Buffer
+		require.NoError(t, WriteMan(&buffer, sub, ManOptions{Section: "8"}))
+		assert.Equal(t, `.TH "APP\-SUB" "8" "" "" ""
+.SH NAME
+app\-sub \- Some sub command
+.SH SYNOPSIS
+.B app sub [flags]
+.SH DESCRIPTION
+Some sub command
+.SH OPTIONS
+.TP
+\fB\-\-ints\fR=\fI[]int\fR
+.br
+Multiple values can be given comma\-separated.
+.TP
+\fB\-\-name\fR=\fIname\fR
+Some name to use
+.br
+Required.
+.br
+Environment variable: SOME_NAME
+.br
+Config key: some.name
+.TP
+\fB\-\-old\fR=\fIstring\fR
+.br
+Deprecated: use \-\-name instead
+.TP
+\fB\-o\fR, \fB\-\-output\fR=\fIdoc.someOutput\fR
+.br
+Default: text
+.br
+Allowed values: text, json
+.SH OPTIONS INHERITED FROM PARENT COMMANDS
+.TP
+\fB\-v\fR, \fB\-\-verbose\fR
+Be verbose
+.SH EXIT STATUS
+.TP
+.B 0
+Successful execution.
+.TP
+.B 1
+Invalid usage, such as invalid flag values, or failed execution.
+.PP
+Other exit codes may be returned by the command itself.
+.SH SEE ALSO
+\fBapp\fP(8)
+`, buffer.String())
+	})
+}
+
+func TestNewManCommand(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, newSomeCommand().Execute(command.WithArgs("gen-man", "--dir", dir), command.AssertExitCode(t, 0)))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var fileNames []string
+	for _, entry := range entries {
+		fileNames = append(fileNames, entry.Name())
+	}
+	assert.Equal(t, []string{"app-sub.1", "app.1"}, fileNames)
+	content, err := os.ReadFile(filepath.Join(dir, "app.1"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `.SH NAME`)
+}
@@ -0,0 +1,330 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neiser/go-nagini/command"
+)
+
+// Format is the markup format of the reference documentation, see GenerateReferenceTree.
+type Format string
+
+const (
+	// Markdown generates CommonMark with GitHub flavored tables.
+	Markdown Format = "md"
+	// ReStructuredText generates reStructuredText, using the :doc: role of Sphinx for links.
+	ReStructuredText Format = "rst"
+)
+
+// referenceIndexName is the base name of the index page, see GenerateReferenceTree.
+const referenceIndexName = "index"
+
+// GenerateReferenceTree writes one reference page for the given command and each documented sub command
+// into dir, plus an index page listing all commands.
+// Hidden or deprecated commands are skipped.
+// The file names are the command paths joined with dashes, for example "app-sub.md",
+// and the output is deterministic to be checked in and compared.
+func GenerateReferenceTree(root command.Command, dir string, format Format) error {
+	writeFile := func(name string, write func(w io.Writer) error) error {
+		var buffer bytes.Buffer
+		if err := write(&buffer); err != nil {
+			return err
+		}
+		fileName := filepath.Join(dir, name+"."+string(format))
+		if err := os.WriteFile(fileName, buffer.Bytes(), 0o644); err != nil { //nolint:gosec,mnd
+			return fmt.Errorf("cannot write reference page: %w", err)
+		}
+		return nil
+	}
+	for cmd := range root.All() {
+		if !isDocumented(cmd) {
+			continue
+		}
+		if err := writeFile(baseName(cmd.Command), func(w io.Writer) error {
+			return WriteReference(w, cmd, format)
+		}); err != nil {
+			return err
+		}
+	}
+	return writeFile(referenceIndexName, func(w io.Writer) error {
+		return WriteReferenceIndex(w, root, format)
+	})
+}
+
+// WriteReference writes the reference page for the given command in the given format.
+// The page links to the parent commands and sub commands and contains tables of the local and inherited flags.
+func WriteReference(w io.Writer, cmd command.Command, format Format) error {
+	return writeFormatted(w, format, func(r referenceRenderer) {
+		localFlags, inheritedFlags := collectFlags(cmd.LocalFlags()), collectFlags(cmd.InheritedFlags())
+		r.title(cmd.CommandPath())
+		r.breadcrumbs(breadcrumbs(cmd))
+		if cmd.Command.Short != "" {
+			r.paragraph(cmd.Command.Short)
+		}
+		if cmd.Command.Long != "" {
+			for _, paragraph := range strings.Split(cmd.Command.Long, "\n\n") {
+				r.paragraph(paragraph)
+			}
+		}
+		r.heading("Usage")
+		r.codeBlock(cmd.UseLine())
+		if len(localFlags) > 0 {
+			r.heading("Flags")
+			r.flagTable(localFlags)
+		}
+		if len(inheritedFlags) > 0 {
+			r.heading("Inherited flags")
+			r.flagTable(inheritedFlags)
+		}
+		if children := documentedChildren(cmd); len(children) > 0 {
+			r.heading("Subcommands")
+			for _, child := range children {
+				r.listItem(0, r.link(child.CommandPath(), baseName(child.Command)), child.Command.Short)
+			}
+		}
+	})
+}
+
+// WriteReferenceIndex writes the index page listing all documented commands of the given root command.
+func WriteReferenceIndex(w io.Writer, root command.Command, format Format) error {
+	return writeFormatted(w, format, func(r referenceRenderer) {
+		r.title("Command reference")
+		for cmd := range root.All() {
+			if !isDocumented(cmd) {
+				continue
+			}
+			depth := -1
+			for range cmd.Parents() {
+				depth++
+			}
+			r.listItem(depth, r.link(cmd.CommandPath(), baseName(cmd.Command)), cmd.Command.Short)
+		}
+	})
+}
+
+// breadcrumb is a link to a parent command, see breadcrumbs.
+type breadcrumb struct {
+	name     string
+	baseName string
+}
+
+// breadcrumbs returns the parents of the given command, starting from the root command.
+func breadcrumbs(cmd command.Command) (result []breadcrumb) {
+	for parent := range cmd.Parents() {
+		if parent.Command == cmd.Command {
+			continue
+		}
+		result = append([]breadcrumb{{parent.Name(), baseName(parent.Command)}}, result...)
+	}
+	return
+}
+
+// referenceRenderer renders the elements of a reference page in a specific Format.
+type referenceRenderer interface {
+	title(text string)
+	heading(text string)
+	breadcrumbs(parents []breadcrumb)
+	paragraph(text string)
+	codeBlock(code string)
+	listItem(depth int, text, description string)
+	link(text, baseName string) string
+	flagTable(flags []flagInfo)
+}
+
+//nolint:wrapcheck
+func writeFormatted(w io.Writer, format Format, render func(r referenceRenderer)) error {
+	var buffer bytes.Buffer
+	switch format {
+	case Markdown:
+		render(&markdownRenderer{&buffer})
+	case ReStructuredText:
+		render(&rstRenderer{&buffer})
+	default:
+		return fmt.Errorf("unknown documentation format '%s'", format) //nolint:err113
+	}
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// flagTableHeader is the header of flag tables, see flagTableRow.
+func flagTableHeader() []string {
+	return []string{"Name", "Shorthand", "Type", "Default", "Required", "Environment variable", "Config key", "Description"}
+}
+
+// flagTableRow returns the cells of the flag table in the order of flagTableHeader.
+func flagTableRow(f flagInfo, code func(s string) string) []string {
+	optionalCode := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return code(s)
+	}
+	required := ""
+	if f.Required {
+		required = "yes"
+	}
+	description := []string{f.Usage}
+	if len(f.EnumValues) > 0 {
+		description = append(description, fmt.Sprintf("Allowed values: %s.", strings.Join(f.EnumValues, ", ")))
+	}
+	if f.Slice {
+		description = append(description, "Multiple values can be given comma-separated.")
+	}
+	if f.Deprecated != "" {
+		description = append(description, fmt.Sprintf("Deprecated: %s", f.Deprecated))
+	}
+	var shorthand string
+	if f.Shorthand != "" {
+		shorthand = code("-" + f.Shorthand)
+	}
+	return []string{
+		code("--" + f.Name),
+		shorthand,
+		optionalCode(f.Type),
+		optionalCode(f.Default),
+		required,
+		optionalCode(f.Binding.EnvVar),
+		optionalCode(f.Binding.ConfigKey),
+		strings.TrimSpace(strings.Join(description, " ")),
+	}
+}
+
+type markdownRenderer struct {
+	*bytes.Buffer
+}
+
+func (r *markdownRenderer) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(r, format, args...)
+}
+
+func (r *markdownRenderer) title(text string) {
+	r.printf("# %s\n\n", text)
+}
+
+func (r *markdownRenderer) heading(text string) {
+	r.printf("## %s\n\n", text)
+}
+
+func (r *markdownRenderer) breadcrumbs(parents []breadcrumb) {
+	if len(parents) == 0 {
+		return
+	}
+	links := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		links = append(links, r.link(parent.name, parent.baseName))
+	}
+	r.printf("%s\n\n", strings.Join(links, " › "))
+}
+
+func (r *markdownRenderer) paragraph(text string) {
+	r.printf("%s\n\n", text)
+}
+
+func (r *markdownRenderer) codeBlock(code string) {
+	r.printf("```\n%s\n```\n\n", code)
+}
+
+func (r *markdownRenderer) listItem(depth int, text, description string) {
+	r.printf("%s- %s", strings.Repeat("  ", depth), text)
+	if description != "" {
+		r.printf(" - %s", description)
+	}
+	r.printf("\n")
+}
+
+func (r *markdownRenderer) link(text, baseName string) string {
+	return fmt.Sprintf("[%s](%s.%s)", text, baseName, Markdown)
+}
+
+func (r *markdownRenderer) flagTable(flags []flagInfo) {
+	header := flagTableHeader()
+	r.printf("| %s |\n", strings.Join(header, " | "))
+	r.printf("|%s\n", strings.Repeat("---|", len(header)))
+	for _, f := range flags {
+		cells := flagTableRow(f, func(s string) string {
+			return "`" + s + "`"
+		})
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		r.printf("| %s |\n", strings.Join(cells, " | "))
+	}
+	r.printf("\n")
+}
+
+type rstRenderer struct {
+	*bytes.Buffer
+}
+
+func (r *rstRenderer) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(r, format, args...)
+}
+
+func (r *rstRenderer) title(text string) {
+	r.printf("%s\n%s\n\n", text, strings.Repeat("=", len(text)))
+}
+
+func (r *rstRenderer) heading(text string) {
+	r.printf("%s\n%s\n\n", text, strings.Repeat("-", len(text)))
+}
+
+func (r *rstRenderer) breadcrumbs(parents []breadcrumb) {
+	if len(parents) == 0 {
+		return
+	}
+	links := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		links = append(links, r.link(parent.name, parent.baseName))
+	}
+	r.printf("%s\n\n", strings.Join(links, " › "))
+}
+
+func (r *rstRenderer) paragraph(text string) {
+	r.printf("%s\n\n", text)
+}
+
+func (r *rstRenderer) codeBlock(code string) {
+	r.printf(".. code-block:: text\n\n   %s\n\n", code)
+}
+
+// listItem ignores the depth, as nested lists need blank lines in between.
+// The link text is the full command path anyway.
+func (r *rstRenderer) listItem(_ int, text, description string) {
+	r.printf("- %s", text)
+	if description != "" {
+		r.printf(" - %s", description)
+	}
+	r.printf("\n")
+}
+
+func (r *rstRenderer) link(text, baseName string) string {
+	return fmt.Sprintf(":doc:`%s <%s>`", text, baseName)
+}
+
+func (r *rstRenderer) flagTable(flags []flagInfo) {
+	r.printf(".. list-table::\n   :header-rows: 1\n\n")
+	writeRow := func(cells []string) {
+		for i, cell := range cells {
+			prefix := "     -"
+			if i == 0 {
+				prefix = "   * -"
+			}
+			if cell != "" {
+				prefix += " "
+			}
+			r.printf("%s%s\n", prefix, cell)
+		}
+	}
+	writeRow(flagTableHeader())
+	for _, f := range flags {
+		writeRow(flagTableRow(f, func(s string) string {
+			return "``" + s + "``"
+		}))
+	}
+	r.printf("\n")
+}
@@ -0,0 +1,119 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neiser/go-nagini/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCommand(root command.Command, name string) command.Command {
+	for cmd := range root.All() {
+		if cmd.Name() == name {
+			return cmd
+		}
+	}
+	panic("cannot find command " + name)
+}
+
+func TestWriteReference(t *testing.T) {
+	cmd := newSomeCommand()
+
+	t.Run("markdown", func(t *testing.T) {
+		var buffer bytes.Buffer
+		require.NoError(t, WriteReference(&buffer, findCommand(cmd, "sub"), Markdown))
+		assert.Equal(t, "# app sub\n\n[app](app.md)\n\nSome sub command\n\n## Usage\n\n```\napp sub [flags]\n```\n\n"+
+			`## Flags
+
+| Name | Shorthand | Type | Default | Required | Environment variable | Config key | Description |
+|---|---|---|---|---|---|---|---|
+| `+"`--ints`"+` |  | `+"`[]int`"+` |  |  |  |  | Multiple values can be given comma-separated. |
+| `+"`--name`"+` |  | `+"`name`"+` |  | yes | `+"`SOME_NAME`"+` | `+"`some.name`"+` | Some name to use |
+| `+"`--old`"+` |  | `+"`string`"+` |  |  |  |  | Deprecated: use --name instead |
+| `+"`--output`"+` | `+"`-o`"+` | `+"`doc.someOutput`"+` | `+"`text`"+` |  |  |  | Allowed values: text, json. |
+
+## Inherited flags
+
+| Name | Shorthand | Type | Default | Required | Environment variable | Config key | Description |
+|---|---|---|---|---|---|---|---|
+| `+"`--verbose`"+` | `+"`-v`"+` |  |  |  |  |  | Be verbose |
+
+`, buffer.String())
+	})
+
+	t.Run("restructured text", func(t *testing.T) {
+		var buffer bytes.Buffer
+		require.NoError(t, WriteReference(&buffer, cmd, ReStructuredText))
+		assert.Equal(t, `app
+===
+
+Some app
+
+Does something.
+
+Really.
+
+Usage
+-----
+
+.. code-block:: text
+
+   app [flags]
+
+Flags
+-----
+
+.. list-table::
+   :header-rows: 1
+
+   * - Name
+     - Shorthand
+     - Type
+     - Default
+     - Required
+     - Environment variable
+     - Config key
+     - Description
+   * - `+"``--verbose``"+`
+     - `+"``-v``"+`
+     -
+     -
+     -
+     -
+     -
+     - Be verbose
+
+Subcommands
+-----------
+
+- :doc:`+"`app sub <app-sub>`"+` - Some sub command
+`, buffer.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		require.EqualError(t, WriteReference(&bytes.Buffer{}, cmd, "html"), "unknown documentation format 'html'")
+	})
+}
+
+func TestGenerateReferenceTree(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, GenerateReferenceTree(newSomeCommand(), dir, Markdown))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var fileNames []string
+	for _, entry := range entries {
+		fileNames = append(fileNames, entry.Name())
+	}
+	assert.Equal(t, []string{"app-sub.md", "app.md", "index.md"}, fileNames)
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `# Command reference
+
+- [app](app.md) - Some app
+  - [app sub](app-sub.md) - Some sub command
+`, string(index))
+}
@@ -0,0 +1,86 @@
+package flag
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Alias is an additional name of a flag, see RegisterOptions.Aliases.
+// Using the alias on the command line sets the same target as the flag itself.
+type Alias struct {
+	// Name is the alias flag name (double dash prefix).
+	Name string
+	// Hidden hides the alias from the usage help output.
+	Hidden bool
+	// Deprecated prints a warning if the alias is used, such as "Flag --old has been deprecated, use --new instead".
+	// See [github.com/neiser/go-nagini/command.WithDeprecationWarnings].
+	Deprecated bool
+}
+
+// WithAliases adds the given names as visible aliases of the flag as a RegisterModifier.
+func WithAliases(names ...string) RegisterModifier {
+	return func(options *RegisterOptions) {
+		for _, name := range names {
+			options.Aliases = append(options.Aliases, Alias{Name: name})
+		}
+	}
+}
+
+// RenamedFrom adds the old name of a renamed flag as a hidden and deprecated Alias as a RegisterModifier.
+// The registered flag receives all values given with the old name,
+// for example RegisterOptions{Name: "output"}.Apply(RenamedFrom("output-file")) deprecates --output-file.
+func RenamedFrom(oldName string) RegisterModifier {
+	return func(options *RegisterOptions) {
+		options.Aliases = append(options.Aliases, Alias{Name: oldName, Hidden: true, Deprecated: true})
+	}
+}
+
+// DeprecatedBy is the same as RenamedFrom, as the registered flag deprecates the given old name.
+func DeprecatedBy(oldName string) RegisterModifier {
+	return RenamedFrom(oldName)
+}
+
+// AliasValue is the value of a flag registered for an Alias, see RegisterOptions.Aliases.
+// Setting it sets the wrapped Value of the aliased Flag, which is then marked as Changed as well.
+// Implements WrappingValue.
+type AliasValue struct {
+	Value
+
+	Alias Alias
+	// Flag is the aliased flag.
+	Flag *pflag.Flag
+}
+
+// Unwrap implements WrappingValue.
+func (a AliasValue) Unwrap() Value {
+	return a.Value
+}
+
+// Set implements pflag.Value.
+//
+//nolint:wrapcheck
+func (a AliasValue) Set(s string) error {
+	if err := a.Value.Set(s); err != nil {
+		return err
+	}
+	a.Flag.Changed = true
+	return nil
+}
+
+// DeprecationWarning returns the warning printed if the alias is used and deprecated, see Alias.Deprecated.
+func (a AliasValue) DeprecationWarning() string {
+	return fmt.Sprintf("Flag --%s has been deprecated, use --%s instead", a.Alias.Name, a.Flag.Name)
+}
+
+// registerAliases registers one flag per alias in the given flag set, see RegisterOptions.Aliases.
+func registerAliases(flags *pflag.FlagSet, aliased *pflag.Flag, value Value, aliases []Alias) {
+	for _, alias := range aliases {
+		aliasFlag := flags.VarPF(AliasValue{value, alias, aliased}, alias.Name, "", "Alias for --"+aliased.Name)
+		aliasFlag.NoOptDefVal = aliased.NoOptDefVal
+		aliasFlag.Hidden = alias.Hidden
+		if group, found := aliased.Annotations[GroupAnnotation]; found {
+			aliasFlag.Annotations = map[string][]string{GroupAnnotation: group}
+		}
+	}
+}
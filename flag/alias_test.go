@@ -0,0 +1,37 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	options := RegisterOptions{Name: "new", Group: "Some"}.Apply(WithAliases("alias"), RenamedFrom("old"))
+	assert.Equal(t, []Alias{{Name: "alias"}, {Name: "old", Hidden: true, Deprecated: true}}, options.Aliases)
+
+	var target bool
+	value := Bool(&target)
+	cmd := &cobra.Command{}
+	newFlag := cmd.Flags().VarPF(value, options.Name, "", "")
+	options.AfterRegistration(cmd, newFlag, value)
+
+	oldFlag := cmd.Flags().Lookup("old")
+	require.NotNil(t, oldFlag)
+	assert.True(t, oldFlag.Hidden)
+	assert.Equal(t, "true", oldFlag.NoOptDefVal)
+	assert.Equal(t, []string{"Some"}, oldFlag.Annotations[GroupAnnotation])
+
+	require.NoError(t, cmd.Flags().Parse([]string{"--old"}))
+	assert.True(t, target)
+	assert.True(t, newFlag.Changed)
+	aliasValue, ok := ValueAs[AliasValue](oldFlag.Value)
+	require.True(t, ok)
+	assert.Equal(t, "Flag --old has been deprecated, use --new instead", aliasValue.DeprecationWarning())
+	assert.False(t, cmd.Flags().Lookup("alias").Hidden)
+
+	assert.Equal(t, RegisterOptions{Name: "new"}.Apply(RenamedFrom("old")),
+		RegisterOptions{Name: "new"}.Apply(DeprecatedBy("old")))
+}
@@ -12,10 +12,43 @@ type Binding interface {
 	BindTo() Binder
 }
 
+// BindingInfo describes the external configuration a flag is bound to, see DescribedBinding.
+type BindingInfo struct {
+	// EnvVar is the environment variable the flag is bound to, if any.
+	EnvVar string
+	// ConfigKey is the configuration key the flag is bound to, if any.
+	ConfigKey string
+}
+
+// DescribedBinding can be implemented by a Binding to describe the binding,
+// for example for generated documentation.
+type DescribedBinding interface {
+	Binding
+	DescribeBinding() BindingInfo
+}
+
 // Binder is called during command execution to actually bind the flag.
 // Binding is deferred to ensure that the flag value has been parsed properly.
 type Binder func(flag *pflag.Flag) error
 
+// BindingErrorCollector can be implemented by the [pflag.Value] of a registered flag
+// to collect the error returned by its Binder instead of failing the command execution immediately.
+// See [github.com/neiser/go-nagini/command.WithAggregatedErrors].
+type BindingErrorCollector interface {
+	CollectBindingError(err error)
+}
+
+// bind runs the given Binder for the flag and passes a returned error to the
+// BindingErrorCollector of the flag value, if implemented.
+func bind(binder Binder, flag *pflag.Flag) error {
+	err := binder(flag)
+	if collector, ok := flag.Value.(BindingErrorCollector); ok && err != nil {
+		collector.CollectBindingError(err)
+		return nil
+	}
+	return err
+}
+
 type cobraRunFuncPtr *func(cmd *cobra.Command, args []string) error
 
 // addToPreRunE adds the given action to the command PreRunE phase.
@@ -2,6 +2,7 @@ package binding
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/neiser/go-nagini/flag"
 	"github.com/spf13/pflag"
@@ -16,6 +17,11 @@ type Viper struct {
 	ConfigKey string
 }
 
+// Unwrap implements flag.WrappingValue.
+func (v Viper) Unwrap() flag.Value {
+	return v.Value
+}
+
 // BindTo binds the flag of the command to a viper configuration value.
 func (v Viper) BindTo() flag.Binder {
 	if v.ConfigKey == "" {
@@ -36,13 +42,54 @@ func (v Viper) BindTo() flag.Binder {
 		// the flag has not been changed on the cmd lint.
 		// This makes a flag-set value precedent over viper values
 		if configValuePresent && !flag.Changed {
-			return v.setValueFromViper()
+			return v.setValueFromViper(flag)
 		}
 		return nil
 	}
 }
 
-func (v Viper) setValueFromViper() error {
+// DescribeBinding implements flag.DescribedBinding.
+// The environment variable is derived from the ConfigKey and the environment prefix of Viper,
+// assuming that [viper.AutomaticEnv] is used.
+// The environment variable is left empty if the ConfigKey requires a key replacer of Viper,
+// such as "some.name", as the replacer cannot be determined. Wrap the binding in [flag.Env] to document it.
+func (v Viper) DescribeBinding() flag.BindingInfo {
+	if v.ConfigKey == "" {
+		return flag.BindingInfo{}
+	}
+	info := flag.BindingInfo{ConfigKey: v.ConfigKey}
+	envVar := strings.ToUpper(v.ConfigKey)
+	if prefix := viper.GetEnvPrefix(); prefix != "" {
+		envVar = strings.ToUpper(prefix) + "_" + envVar
+	}
+	isValidEnvVar := strings.IndexFunc(envVar, func(r rune) bool {
+		return r != '_' && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	}) < 0
+	if isValidEnvVar {
+		info.EnvVar = envVar
+	}
+	return info
+}
+
+// setValueFromViper returns errors as flag.SourceError, see source.
+// Records the source of the value with flag.SetSource.
+func (v Viper) setValueFromViper(f *pflag.Flag) error {
+	if err := v.replaceValueFromViper(); err != nil {
+		return flag.SourceError{Source: v.source(), Wrapped: err}
+	}
+	flag.SetSource(f, v.source())
+	return nil
+}
+
+// source determines if the config value originates from the config file, otherwise assumes environment.
+func (v Viper) source() flag.Source {
+	if viper.InConfig(v.ConfigKey) {
+		return flag.SourceConfig
+	}
+	return flag.SourceEnv
+}
+
+func (v Viper) replaceValueFromViper() error {
 	// If the current flag value, and we have something set from Viper,
 	// we set the current value to the viper config value.
 	if sliceValue, ok := v.Value.(pflag.SliceValue); ok {
@@ -64,3 +111,10 @@ func (v Viper) setValueFromViper() error {
 	}
 	return nil
 }
+
+// ViperConfig binds struct fields having a config tag to Viper, see [flag.FromStruct].
+func ViperConfig() flag.StructOption {
+	return flag.WithConfigBinding(func(value flag.Value, configKey string) flag.Value {
+		return Viper{Value: value, ConfigKey: configKey}
+	})
+}
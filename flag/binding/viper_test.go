@@ -1,13 +1,60 @@
 package binding
 
 import (
+	"strconv"
 	"testing"
 
+	"github.com/neiser/go-nagini/flag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestViper_BindTo(t *testing.T) {
 	t.Run("empty config key returns nil binder", func(t *testing.T) {
 		assert.Nil(t, Viper{}.BindTo())
 	})
+
+	t.Run("invalid value is annotated with source", func(t *testing.T) {
+		viper.AutomaticEnv()
+		t.Setenv("SOME_BINDING_INT", "x1x")
+		var (
+			someInt int
+		)
+		value := Viper{Value: flag.String(&someInt, strconv.Atoi), ConfigKey: "SOME_BINDING_INT"}
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		err := value.BindTo()(flags.VarPF(value, "some-int", "", ""))
+		var errWithSource flag.SourceError
+		require.ErrorAs(t, err, &errWithSource)
+		assert.Equal(t, flag.SourceEnv, errWithSource.Source)
+		assert.EqualError(t, err, `cannot set value to viper config SOME_BINDING_INT='x1x': strconv.Atoi: parsing "x1x": invalid syntax`)
+	})
+}
+
+func TestViper_negatable(t *testing.T) {
+	viper.AutomaticEnv()
+	t.Setenv("SOME_BINDING_COLOR", "true")
+	var color bool
+	value := Viper{Value: flag.Bool(&color), ConfigKey: "SOME_BINDING_COLOR"}
+	cmd := &cobra.Command{}
+	options := flag.RegisterOptions{Name: "color", Negatable: true}
+	colorFlag := cmd.Flags().VarPF(value, options.Name, "", "")
+	options.AfterRegistration(cmd, colorFlag, value)
+
+	require.NoError(t, cmd.Flags().Parse([]string{"--no-color"}))
+	require.NoError(t, value.BindTo()(colorFlag))
+	assert.False(t, color)
+	assert.False(t, viper.GetBool("SOME_BINDING_COLOR"))
+}
+
+func TestViper_DescribeBinding(t *testing.T) {
+	var someName string
+	value := flag.String(&someName, flag.NotEmpty)
+	assert.Equal(t, flag.BindingInfo{}, Viper{Value: value}.DescribeBinding())
+	assert.Equal(t, flag.BindingInfo{EnvVar: "SOME_NAME", ConfigKey: "some_name"},
+		Viper{Value: value, ConfigKey: "some_name"}.DescribeBinding())
+	// depends on the key replacer of Viper
+	assert.Equal(t, flag.BindingInfo{ConfigKey: "some.name"}, Viper{Value: value, ConfigKey: "some.name"}.DescribeBinding())
 }
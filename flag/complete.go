@@ -0,0 +1,92 @@
+package flag
+
+import (
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CompleteFunc returns the shell completion candidates of a flag for the partial input toComplete,
+// see RegisterOptions.Complete. The given flags are the flags of the command,
+// already parsed from the command line being completed.
+type CompleteFunc func(toComplete string, flags *pflag.FlagSet) ([]string, cobra.ShellCompDirective)
+
+// Completer can be implemented by a Value to provide shell completions of the flag,
+// unless RegisterOptions.Complete is set. See for example Enum and Bool.
+type Completer interface {
+	Complete(toComplete string) ([]string, cobra.ShellCompDirective)
+}
+
+// CompleteValues completes the given fixed values.
+func CompleteValues(values ...string) CompleteFunc {
+	return func(string, *pflag.FlagSet) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CompleteFileExtensions completes the files having one of the given extensions, such as "yaml" or "json".
+func CompleteFileExtensions(extensions ...string) CompleteFunc {
+	return func(string, *pflag.FlagSet) ([]string, cobra.ShellCompDirective) {
+		return extensions, cobra.ShellCompDirectiveFilterFileExt
+	}
+}
+
+// CompleteDirectories completes directories only.
+func CompleteDirectories() CompleteFunc {
+	return func(string, *pflag.FlagSet) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+}
+
+// registerCompletion registers the completion of the flags with the given names,
+// using RegisterOptions.Complete or the Completer of the value.
+func (o RegisterOptions) registerCompletion(cmd *cobra.Command, value Value, flagNames ...string) {
+	complete := o.Complete
+	if complete == nil {
+		completer, ok := ValueAs[Completer](value)
+		if !ok {
+			return
+		}
+		complete = func(toComplete string, _ *pflag.FlagSet) ([]string, cobra.ShellCompDirective) {
+			return completer.Complete(toComplete)
+		}
+	}
+	for _, flagName := range flagNames {
+		// only fails if the flag is registered twice, which pflag does not allow anyway
+		_ = cmd.RegisterFlagCompletionFunc(flagName,
+			func(cmd *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+				return complete(toComplete, cmd.Flags())
+			})
+	}
+}
+
+// Complete implements Completer for booleans and [time.Duration],
+// otherwise uses the default completion of the shell.
+func (v anyValue[T]) Complete(toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch any(*v.target).(type) {
+	case bool:
+		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
+	case time.Duration:
+		return completeDuration(toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	default:
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+}
+
+// completeDuration suggests the units of [time.ParseDuration] for the partial input, if it ends with a number.
+func completeDuration(toComplete string) (result []string) {
+	if toComplete == "" || !unicode.IsDigit(rune(toComplete[len(toComplete)-1])) {
+		return nil
+	}
+	for _, unit := range []string{"ns", "us", "ms", "s", "m", "h"} {
+		result = append(result, toComplete+unit)
+	}
+	return
+}
+
+// Complete implements Completer.
+func (v enumValue[T]) Complete(string) ([]string, cobra.ShellCompDirective) {
+	return v.EnumValues(), cobra.ShellCompDirectiveNoFileComp
+}
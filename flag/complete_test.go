@@ -0,0 +1,63 @@
+package flag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteFuncs(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		complete          CompleteFunc
+		expected          []string
+		expectedDirective cobra.ShellCompDirective
+	}{
+		{"values", CompleteValues("a", "b"), []string{"a", "b"}, cobra.ShellCompDirectiveNoFileComp},
+		{"file extensions", CompleteFileExtensions("yaml", "json"), []string{"yaml", "json"}, cobra.ShellCompDirectiveFilterFileExt},
+		{"directories", CompleteDirectories(), nil, cobra.ShellCompDirectiveFilterDirs},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			completions, directive := tt.complete("", pflag.NewFlagSet("", pflag.ContinueOnError))
+			assert.Equal(t, tt.expected, completions)
+			assert.Equal(t, tt.expectedDirective, directive)
+		})
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	var (
+		someBool     bool
+		someDuration time.Duration
+		someString   string
+		someEnum     string
+	)
+	for _, tt := range []struct {
+		name              string
+		value             Value
+		toComplete        string
+		expected          []string
+		expectedDirective cobra.ShellCompDirective
+	}{
+		{"bool", Bool(&someBool), "", []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp},
+		{"duration", String(&someDuration, time.ParseDuration), "5", []string{"5ns", "5us", "5ms", "5s", "5m", "5h"},
+			cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace},
+		{"duration without number", String(&someDuration, time.ParseDuration), "5m", nil,
+			cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace},
+		{"string", String(&someString, NotEmpty), "", nil, cobra.ShellCompDirectiveDefault},
+		{"enum", Enum(&someEnum, "a", "b"), "", []string{"a", "b"}, cobra.ShellCompDirectiveNoFileComp},
+		{"wrapped enum", Env{Enum(&someEnum, "a", "b"), "SOME_ENV"}, "", []string{"a", "b"}, cobra.ShellCompDirectiveNoFileComp},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			completer, ok := ValueAs[Completer](tt.value)
+			require.True(t, ok)
+			completions, directive := completer.Complete(tt.toComplete)
+			assert.Equal(t, tt.expected, completions)
+			assert.Equal(t, tt.expectedDirective, directive)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package flag
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EnumValue can be implemented by a Value to declare the allowed string values of a flag.
+// This is used for example for generated documentation.
+// See Enum.
+type EnumValue interface {
+	Value
+	EnumValues() []string
+}
+
+// Enum constructs a new flag which only accepts one of the given allowed values.
+// See also String and OneOf.
+func Enum[T ~string](target *T, allowed ...T) EnumValue {
+	return enumValue[T]{
+		anyValue: anyValue[T]{target: target, parser: OneOf(allowed...)},
+		allowed:  allowed,
+	}
+}
+
+type enumValue[T ~string] struct {
+	anyValue[T]
+
+	allowed []T
+}
+
+func (v enumValue[T]) EnumValues() (result []string) {
+	for _, value := range v.allowed {
+		result = append(result, string(value))
+	}
+	return
+}
+
+// OneOf returns a Parser which only accepts one of the given allowed values.
+func OneOf[T ~string](allowed ...T) Parser[T] {
+	return func(s string) (T, error) {
+		if slices.Contains(allowed, T(s)) {
+			return T(s), nil
+		}
+		allowedValues := make([]string, 0, len(allowed))
+		for _, value := range allowed {
+			allowedValues = append(allowedValues, string(value))
+		}
+		return "", fmt.Errorf("%w: value '%s' must be one of %s", ErrParser, s, strings.Join(allowedValues, ", "))
+	}
+}
@@ -0,0 +1,22 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnum(t *testing.T) {
+	type someEnum string
+	var (
+		target someEnum = "a"
+	)
+	sut := Enum(&target, "a", "b")
+	assert.Equal(t, []string{"a", "b"}, sut.EnumValues())
+	assert.Equal(t, "flag.someEnum", sut.Type())
+	require.NoError(t, sut.Set("b"))
+	assert.Equal(t, someEnum("b"), target)
+	assert.Equal(t, "b", sut.String())
+	require.EqualError(t, sut.Set("c"), "cannot parse parameter: value 'c' must be one of a, b")
+}
@@ -0,0 +1,65 @@
+package flag
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// Env binds a command flag (given by a Value instance) to the environment variable EnvVar.
+// The environment variable is used if the flag has not been set on the command line.
+// If the wrapped Value implements Binding itself, such as [github.com/neiser/go-nagini/flag/binding.Viper],
+// that binding is run first, so the environment variable takes precedence over it.
+// Implements Binding.
+type Env struct {
+	Value
+
+	EnvVar string
+}
+
+// Unwrap implements WrappingValue.
+func (e Env) Unwrap() Value {
+	return e.Value
+}
+
+// BindTo implements Binding.
+func (e Env) BindTo() Binder {
+	var wrapped Binder
+	if binding, ok := e.Value.(Binding); ok {
+		wrapped = binding.BindTo()
+	}
+	if e.EnvVar == "" {
+		return wrapped
+	}
+	return func(flag *pflag.Flag) error {
+		if wrapped != nil {
+			if err := wrapped(flag); err != nil {
+				return err
+			}
+		}
+		envValue, found := os.LookupEnv(e.EnvVar)
+		if !found || flag.Changed {
+			return nil
+		}
+		if err := e.Set(envValue); err != nil {
+			return SourceError{
+				Source:  SourceEnv,
+				Wrapped: fmt.Errorf("cannot set value from environment variable %s='%s': %w", e.EnvVar, envValue, err),
+			}
+		}
+		SetSource(flag, SourceEnv)
+		return nil
+	}
+}
+
+// DescribeBinding implements DescribedBinding.
+func (e Env) DescribeBinding() (info BindingInfo) {
+	if binding, ok := e.Value.(DescribedBinding); ok {
+		info = binding.DescribeBinding()
+	}
+	if e.EnvVar != "" {
+		info.EnvVar = e.EnvVar
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package flag
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv_BindTo(t *testing.T) {
+	newFlag := func(value Value) *pflag.Flag {
+		return pflag.NewFlagSet("test", pflag.ContinueOnError).VarPF(value, "some-int", "", "")
+	}
+
+	t.Run("empty env var returns nil binder", func(t *testing.T) {
+		var someInt int
+		assert.Nil(t, Env{Value: String(&someInt, strconv.Atoi)}.BindTo())
+	})
+
+	t.Run("value from environment", func(t *testing.T) {
+		t.Setenv("SOME_ENV_INT", "42")
+		var someInt int
+		value := Env{Value: String(&someInt, strconv.Atoi), EnvVar: "SOME_ENV_INT"}
+		require.NoError(t, value.BindTo()(newFlag(value)))
+		assert.Equal(t, 42, someInt)
+	})
+
+	t.Run("flag takes precedence", func(t *testing.T) {
+		t.Setenv("SOME_ENV_INT", "42")
+		var someInt int
+		value := Env{Value: String(&someInt, strconv.Atoi), EnvVar: "SOME_ENV_INT"}
+		f := newFlag(value)
+		require.NoError(t, f.Value.Set("1"))
+		f.Changed = true
+		require.NoError(t, value.BindTo()(f))
+		assert.Equal(t, 1, someInt)
+	})
+
+	t.Run("unset environment variable", func(t *testing.T) {
+		someInt := 1
+		value := Env{Value: String(&someInt, strconv.Atoi), EnvVar: "SOME_UNSET_ENV_INT"}
+		require.NoError(t, value.BindTo()(newFlag(value)))
+		assert.Equal(t, 1, someInt)
+	})
+
+	t.Run("invalid value is annotated with source", func(t *testing.T) {
+		t.Setenv("SOME_ENV_INT", "x1x")
+		var someInt int
+		value := Env{Value: String(&someInt, strconv.Atoi), EnvVar: "SOME_ENV_INT"}
+		err := value.BindTo()(newFlag(value))
+		var errWithSource SourceError
+		require.ErrorAs(t, err, &errWithSource)
+		assert.Equal(t, SourceEnv, errWithSource.Source)
+		assert.EqualError(t, err, `cannot set value from environment variable SOME_ENV_INT='x1x': strconv.Atoi: parsing "x1x": invalid syntax`)
+	})
+
+	t.Run("wrapped binding runs first", func(t *testing.T) {
+		t.Setenv("SOME_ENV_INT", "42")
+		var someInt int
+		wrappedRuns := 0
+		value := Env{Value: someBinding{String(&someInt, strconv.Atoi), func(*pflag.Flag) error {
+			wrappedRuns++
+			someInt = 3
+			return nil
+		}}, EnvVar: "SOME_ENV_INT"}
+		require.NoError(t, value.BindTo()(newFlag(value)))
+		assert.Equal(t, 1, wrappedRuns)
+		assert.Equal(t, 42, someInt)
+		assert.Equal(t, BindingInfo{EnvVar: "SOME_ENV_INT", ConfigKey: "some.key"}, value.DescribeBinding())
+	})
+}
+
+type someBinding struct {
+	Value
+
+	binder Binder
+}
+
+func (b someBinding) BindTo() Binder {
+	return b.binder
+}
+
+func (b someBinding) DescribeBinding() BindingInfo {
+	return BindingInfo{EnvVar: "SOME_OTHER", ConfigKey: "some.key"}
+}
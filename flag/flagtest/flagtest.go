@@ -0,0 +1,156 @@
+// Package flagtest provides property checks and fuzz targets for implementations of flag.Value,
+// in particular for custom flag.Parser, flag.SliceParser and flag.TargetParser implementations.
+// Like package commandtest, it does not depend on testify.
+package flagtest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+)
+
+// Options configure the checks, see CheckValue and CheckSliceValue.
+type Options struct {
+	// Seeds are added to the seed corpus when fuzzing, in addition to some values known to break parsers.
+	Seeds []string
+	// AllowForeignErrors accepts errors from Set not wrapping flag.ErrParser,
+	// for example when using parsers like [strconv.Atoi].
+	AllowForeignErrors bool
+}
+
+// defaultSeeds are values known to break parsers and comma-separated value handling.
+func defaultSeeds() []string {
+	return []string{"", " ", "0", "-1", "true", "a", " a ", "a,b", `"a"`, `a"b`, `"a,b"`, "[a]", "[", "]", "a\nb", "ä"}
+}
+
+// FuzzValue fuzzes the values constructed by newValue with CheckValue.
+// The function newValue must return a value with a fresh target on each call.
+func FuzzValue(f *testing.F, newValue func() flag.Value, options Options) {
+	f.Helper()
+	for _, seed := range append(defaultSeeds(), options.Seeds...) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		CheckValue(t, newValue, s, options)
+	})
+}
+
+// CheckValue checks the following properties of the values constructed by newValue for the given input:
+//
+//   - Set never panics.
+//   - Errors returned by Set wrap flag.ErrParser, unless Options.AllowForeignErrors is set.
+//   - If Set succeeds, setting the String representation on a new value succeeds and results in the same representation.
+//
+// The function newValue must return a value with a fresh target on each call.
+func CheckValue(t testing.TB, newValue func() flag.Value, s string, options Options) {
+	t.Helper()
+	value := newValue()
+	if !checkSetError(t, fmt.Sprintf("Set(%q)", s), options, func() error {
+		return value.Set(s)
+	}) {
+		return
+	}
+	representation := value.String()
+	roundTripped := newValue()
+	if err := safeCall(func() error { return roundTripped.Set(representation) }); err != nil {
+		t.Errorf("Set(%q) succeeded, but setting its representation Set(%q) failed: %v", s, representation, err)
+		return
+	}
+	if got := roundTripped.String(); got != representation {
+		t.Errorf("Set(%q) has representation %q, but round-trips to %q", s, representation, got)
+	}
+}
+
+// FuzzSliceValue fuzzes the slice values constructed by newValue with CheckSliceValue,
+// using slices with one and two elements.
+// The function newValue must return a value with a fresh target on each call.
+func FuzzSliceValue(f *testing.F, newValue func() flag.SliceValue, options Options) {
+	f.Helper()
+	seeds := append(defaultSeeds(), options.Seeds...)
+	for i, seed := range seeds {
+		f.Add(seed, seeds[(i+1)%len(seeds)])
+	}
+	f.Fuzz(func(t *testing.T, first, second string) {
+		CheckSliceValue(t, newValue, []string{first}, options)
+		CheckSliceValue(t, newValue, []string{first, second}, options)
+	})
+}
+
+// CheckSliceValue checks the properties of CheckValue for the comma-separated representation of the given elements,
+// and that the elements survive the comma-separated representation:
+//
+//   - Replace never panics.
+//   - Errors returned by Replace wrap flag.ErrParser, unless Options.AllowForeignErrors is set.
+//   - If Replace succeeds, setting the String representation on a new value succeeds and results in the same elements.
+//
+// Elements containing carriage returns are skipped, as the underlying [encoding/csv] does not preserve them.
+// The function newValue must return a value with a fresh target on each call.
+func CheckSliceValue(t testing.TB, newValue func() flag.SliceValue, elements []string, options Options) {
+	t.Helper()
+	for _, element := range elements {
+		if strings.Contains(element, "\r") {
+			return
+		}
+	}
+	CheckValue(t, func() flag.Value { return newValue() }, strings.Join(elements, ","), options)
+
+	value := newValue()
+	if !checkSetError(t, fmt.Sprintf("Replace(%q)", elements), options, func() error {
+		return value.Replace(elements)
+	}) {
+		return
+	}
+	parsed := value.GetSlice()
+	if len(parsed) == 0 {
+		// the representation of empty slices is not comma-separated, see flag.Slice
+		return
+	}
+	representation := value.String()
+	roundTripped := newValue()
+	if err := safeCall(func() error { return roundTripped.Set(representation) }); err != nil {
+		t.Errorf("Replace(%q) succeeded, but setting its representation Set(%q) failed: %v", elements, representation, err)
+		return
+	}
+	if got := roundTripped.GetSlice(); !slices.Equal(got, parsed) {
+		t.Errorf("Replace(%q) has elements %q and representation %q, but round-trips to %q",
+			elements, parsed, representation, got)
+	}
+}
+
+// checkSetError calls the given set function and returns true if it succeeded.
+// Reports panics and errors not wrapping flag.ErrParser.
+func checkSetError(t testing.TB, call string, options Options, set func() error) (succeeded bool) {
+	t.Helper()
+	err := safeCall(set)
+	var panicked errPanic
+	switch {
+	case errors.As(err, &panicked):
+		t.Errorf("%s panicked: %v", call, panicked.recovered)
+	case err != nil && !options.AllowForeignErrors && !errors.Is(err, flag.ErrParser):
+		t.Errorf("%s returned error not wrapping flag.ErrParser: %v", call, err)
+	}
+	return err == nil
+}
+
+// errPanic is returned by safeCall.
+type errPanic struct {
+	recovered any
+}
+
+func (e errPanic) Error() string {
+	return fmt.Sprintf("panic: %v", e.recovered)
+}
+
+// safeCall calls the given function and turns a panic into errPanic.
+func safeCall(call func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errPanic{recovered}
+		}
+	}()
+	return call()
+}
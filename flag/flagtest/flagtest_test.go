@@ -0,0 +1,136 @@
+package flagtest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/neiser/go-nagini/flag"
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzString(f *testing.F) {
+	FuzzValue(f, func() flag.Value {
+		var target string
+		return flag.String(&target, flag.NotEmptyTrimmed)
+	}, Options{})
+}
+
+func FuzzInt(f *testing.F) {
+	FuzzValue(f, func() flag.Value {
+		var target int
+		return flag.String(&target, strconv.Atoi)
+	}, Options{Seeds: []string{"+1", "0x10"}, AllowForeignErrors: true})
+}
+
+func FuzzEnum(f *testing.F) {
+	FuzzValue(f, func() flag.Value {
+		var target string
+		return flag.Enum(&target, "a", "b")
+	}, Options{})
+}
+
+func FuzzSlice(f *testing.F) {
+	FuzzSliceValue(f, func() flag.SliceValue {
+		var target []string
+		return flag.Slice(&target, flag.ParseSliceOf[string](flag.AnyString))
+	}, Options{})
+}
+
+func FuzzSliceNotEmpty(f *testing.F) {
+	FuzzSliceValue(f, func() flag.SliceValue {
+		var target []string
+		return flag.Slice(&target, flag.ParseSliceOf[string](flag.NotEmptyTrimmed))
+	}, Options{})
+}
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// someBrokenValue has a lossy representation, fails with foreign errors and panics.
+type someBrokenValue struct {
+	flag.Value
+}
+
+func newSomeBrokenValue() flag.Value {
+	var target string
+	return someBrokenValue{flag.String(&target, func(s string) (string, error) {
+		switch s {
+		case "panic":
+			panic("some panic")
+		case "error":
+			return "", errors.New("some error")
+		default:
+			return s, nil
+		}
+	})}
+}
+
+func (v someBrokenValue) String() string {
+	return "[" + v.Value.String() + "]"
+}
+
+func TestCheckValue(t *testing.T) {
+	t.Run("no failures", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+		CheckValue(fake, func() flag.Value {
+			var target int
+			return flag.String(&target, strconv.Atoi)
+		}, "1", Options{})
+		assert.Empty(t, fake.errors)
+	})
+
+	tests := []struct {
+		input   string
+		options Options
+		want    []string
+	}{
+		{"panic", Options{}, []string{`Set("panic") panicked: some panic`}},
+		{"error", Options{}, []string{`Set("error") returned error not wrapping flag.ErrParser: some error`}},
+		{"error", Options{AllowForeignErrors: true}, nil},
+		{"a", Options{}, []string{`Set("a") has representation "[a]", but round-trips to "[[a]]"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			fake := &fakeT{TB: t}
+			CheckValue(fake, newSomeBrokenValue, tt.input, tt.options)
+			assert.Equal(t, tt.want, fake.errors)
+		})
+	}
+}
+
+func TestCheckSliceValue(t *testing.T) {
+	t.Run("no failures", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+		CheckSliceValue(fake, func() flag.SliceValue {
+			var target []string
+			return flag.Slice(&target, flag.ParseSliceOf[string](flag.AnyString))
+		}, []string{`a "quoted", value`, "[b]"}, Options{})
+		assert.Empty(t, fake.errors)
+	})
+
+	t.Run("not round-tripping", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+		CheckSliceValue(fake, func() flag.SliceValue {
+			var target []string
+			return flag.Slice(&target, func(ss []string) ([]string, error) {
+				return append(ss, "x"), nil
+			})
+		}, []string{"a"}, Options{})
+		assert.Equal(t, []string{
+			`Set("a") has representation "a,x", but round-trips to "a,x,x"`,
+			`Replace(["a"]) has elements ["a" "x"] and representation "a,x", but round-trips to ["a" "x" "x"]`,
+		}, fake.errors)
+	})
+}
@@ -0,0 +1,65 @@
+package flag
+
+import "slices"
+
+// GroupAnnotation is the [pflag.Flag] annotation holding the group the flag belongs to, see RegisterOptions.Group.
+// It is used to render the flags of a group under its own heading in the help output.
+const GroupAnnotation = "nagini_flag_group"
+
+// Group bundles flags together with their constraints, to attach them to several commands.
+// See [github.com/neiser/go-nagini/command.Command.FlagGroup].
+// Use NewGroup to construct one fluently.
+//
+// Commands attaching the same Group share the storage, as the flags are registered with the same targets.
+// To have separate storage per command, construct the Group in a function called for each command.
+type Group struct {
+	// Name is used as heading in the help output, for example "Connection" is shown as "Connection Flags:".
+	Name string
+	// Flags are registered when attaching the group.
+	// Values implementing Binding, such as Env, are bound as usual.
+	Flags []GroupFlag
+	// RequiredTogether holds target pointers of the Flags, see MarkFlagsRequiredTogether.
+	RequiredTogether [][]any
+	// OneRequired holds target pointers of the Flags, see MarkFlagsOneRequired.
+	OneRequired [][]any
+	// MutuallyExclusive holds target pointers of the Flags, see MarkFlagsMutuallyExclusive.
+	MutuallyExclusive [][]any
+}
+
+// GroupFlag is a flag of a Group.
+type GroupFlag struct {
+	Value   Value
+	Options RegisterOptions
+}
+
+// NewGroup constructs an empty Group with the given name.
+func NewGroup(name string) Group {
+	return Group{Name: name}
+}
+
+// Flag adds a flag to the group, see [github.com/neiser/go-nagini/command.Command.Flag].
+func (g Group) Flag(value Value, options RegisterOptions) Group {
+	g.Flags = append(slices.Clip(g.Flags), GroupFlag{value, options})
+	return g
+}
+
+// MarkFlagsRequiredTogether adds the constraint to the group,
+// see [github.com/neiser/go-nagini/command.Command.MarkFlagsRequiredTogether].
+func (g Group) MarkFlagsRequiredTogether(targets ...any) Group {
+	g.RequiredTogether = append(slices.Clip(g.RequiredTogether), targets)
+	return g
+}
+
+// MarkFlagsOneRequired adds the constraint to the group,
+// see [github.com/neiser/go-nagini/command.Command.MarkFlagsOneRequired].
+func (g Group) MarkFlagsOneRequired(targets ...any) Group {
+	g.OneRequired = append(slices.Clip(g.OneRequired), targets)
+	return g
+}
+
+// MarkFlagsMutuallyExclusive adds the constraint to the group,
+// see [github.com/neiser/go-nagini/command.Command.MarkFlagsMutuallyExclusive].
+func (g Group) MarkFlagsMutuallyExclusive(targets ...any) Group {
+	g.MutuallyExclusive = append(slices.Clip(g.MutuallyExclusive), targets)
+	return g
+}
@@ -0,0 +1,34 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	var (
+		host, user string
+		insecure   bool
+	)
+	base := NewGroup("Connection").
+		Flag(String(&host, NotEmpty), RegisterOptions{Name: "host"})
+	withUser := base.Flag(String(&user, NotEmpty), RegisterOptions{Name: "user"}).
+		MarkFlagsRequiredTogether(&host, &user)
+	withInsecure := base.Flag(Bool(&insecure), RegisterOptions{Name: "insecure"}).
+		MarkFlagsOneRequired(&host, &insecure).
+		MarkFlagsMutuallyExclusive(&host, &insecure)
+
+	t.Run("groups derived from the same group are independent", func(t *testing.T) {
+		assert.Len(t, base.Flags, 1)
+		assert.Equal(t, "user", withUser.Flags[1].Options.Name)
+		assert.Equal(t, "insecure", withInsecure.Flags[1].Options.Name)
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		assert.Equal(t, [][]any{{&host, &user}}, withUser.RequiredTogether)
+		assert.Empty(t, withUser.OneRequired)
+		assert.Equal(t, [][]any{{&host, &insecure}}, withInsecure.OneRequired)
+		assert.Equal(t, [][]any{{&host, &insecure}}, withInsecure.MutuallyExclusive)
+	})
+}
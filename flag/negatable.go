@@ -0,0 +1,89 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// Negatable makes a boolean flag negatable as a RegisterModifier, see RegisterOptions.Negatable.
+func Negatable() RegisterModifier {
+	return func(options *RegisterOptions) {
+		options.Negatable = true
+	}
+}
+
+// ErrNegationConflict is returned when a negatable flag is used together with its negation,
+// such as "--color --no-color", see RegisterOptions.Negatable.
+var ErrNegationConflict = errors.New("flag cannot be used together with its negation")
+
+// IsNegatable returns true if the given flag has been registered with RegisterOptions.Negatable.
+func IsNegatable(f *pflag.Flag) bool {
+	_, ok := ValueAs[negatableValue](f.Value)
+	return ok
+}
+
+// negatableValue wraps the Value of a negatable flag to detect conflicting use with its negation.
+type negatableValue struct {
+	Value
+
+	negation *pflag.Flag
+}
+
+// Unwrap implements WrappingValue.
+func (v negatableValue) Unwrap() Value {
+	return v.Value
+}
+
+//nolint:wrapcheck
+func (v negatableValue) Set(s string) error {
+	if v.negation.Changed {
+		return fmt.Errorf("%w --%s", ErrNegationConflict, v.negation.Name)
+	}
+	return v.Value.Set(s)
+}
+
+// negationValue is the value of the "--no-<name>" flag of a negatable flag.
+// Setting it to true sets the negated flag to false, which is then marked as Changed as well.
+type negationValue struct {
+	Value
+
+	negated, negation *pflag.Flag
+}
+
+// Unwrap implements WrappingValue.
+func (v negationValue) Unwrap() Value {
+	return v.Value
+}
+
+//nolint:wrapcheck
+func (v negationValue) Set(s string) error {
+	negate, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParser, err)
+	}
+	if v.negated.Changed && !v.negation.Changed {
+		return fmt.Errorf("%w --%s", ErrNegationConflict, v.negated.Name)
+	}
+	if err := v.Value.Set(strconv.FormatBool(!negate)); err != nil {
+		return err
+	}
+	v.negated.Changed = true
+	return nil
+}
+
+func (v negationValue) String() string {
+	return strconv.FormatBool(v.Value.String() == "false")
+}
+
+// registerNegation registers the hidden "--no-<name>" flag for the given negatable flag.
+func registerNegation(flags *pflag.FlagSet, negated *pflag.Flag, value Value) {
+	negation := flags.VarPF(value, "no-"+negated.Name, "", "Negates --"+negated.Name)
+	negation.Value = negationValue{value, negated, negation}
+	negation.DefValue = "false"
+	negation.NoOptDefVal = "true"
+	negation.Hidden = true
+	negated.Value = negatableValue{value, negation}
+}
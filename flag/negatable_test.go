@@ -0,0 +1,61 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegatable(t *testing.T) {
+	newCommand := func(target *bool) *cobra.Command {
+		options := RegisterOptions{Name: "color"}.Apply(Negatable())
+		value := Bool(target)
+		cmd := &cobra.Command{}
+		options.AfterRegistration(cmd, cmd.Flags().VarPF(value, options.Name, "", ""), value)
+		return cmd
+	}
+
+	for _, tt := range []struct {
+		name     string
+		initial  bool
+		args     []string
+		expected bool
+		changed  bool
+	}{
+		{"no flags", true, nil, true, false},
+		{"negation", true, []string{"--no-color"}, false, true},
+		{"negation set to false", false, []string{"--no-color=false"}, true, true},
+		{"repeated negation", true, []string{"--no-color", "--no-color"}, false, true},
+		{"flag", false, []string{"--color"}, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.initial
+			cmd := newCommand(&target)
+			require.NoError(t, cmd.Flags().Parse(tt.args))
+			assert.Equal(t, tt.expected, target)
+			colorFlag := cmd.Flags().Lookup("color")
+			assert.Equal(t, tt.changed, colorFlag.Changed)
+			assert.True(t, IsNegatable(colorFlag))
+			assert.True(t, cmd.Flags().Lookup("no-color").Hidden)
+		})
+	}
+
+	t.Run("conflicting use", func(t *testing.T) {
+		for _, args := range [][]string{{"--color", "--no-color"}, {"--no-color", "--color"}} {
+			var target bool
+			err := newCommand(&target).Flags().Parse(args)
+			require.ErrorIs(t, err, ErrNegationConflict, args)
+		}
+	})
+
+	t.Run("ignored for non-boolean flags", func(t *testing.T) {
+		var target string
+		options := RegisterOptions{Name: "name", Negatable: true}
+		value := String(&target, NotEmpty)
+		cmd := &cobra.Command{}
+		options.AfterRegistration(cmd, cmd.Flags().VarPF(value, options.Name, "", ""), value)
+		assert.Nil(t, cmd.Flags().Lookup("no-name"))
+	})
+}
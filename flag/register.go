@@ -26,8 +26,30 @@ type RegisterOptions struct {
 	// The flag is then inherited to sub commands.
 	// See RegisterOptions.SelectFlags
 	Persistent bool
+	// Group shows the flag under its own heading in the help output, for example "Connection" as "Connection Flags:".
+	// See also [github.com/neiser/go-nagini/command.Command.FlagGroups].
+	Group string
+	// Aliases are additional names of the flag, which set the same target.
+	// The flag is then Changed if any of its aliases is used, and constraints,
+	// such as [github.com/neiser/go-nagini/command.Command.MarkFlagsMutuallyExclusive], treat them as one flag.
+	// See also WithAliases, RenamedFrom and DeprecatedBy.
+	Aliases []Alias
+	// Negatable registers the hidden flag "--no-<name>" for a boolean flag, which sets the same target to false.
+	// The help output shows both forms as "--[no-]<name>" and using both is an error, see ErrNegationConflict.
+	// Bindings, such as [github.com/neiser/go-nagini/flag/binding.Viper], consider both forms as the flag itself.
+	// Ignored for non-boolean flags.
+	Negatable bool
+	// Secret prompts for the value of a missing required flag without echoing the input,
+	// see [github.com/neiser/go-nagini/command.WithPrompting].
+	Secret bool
+	// Complete provides the shell completions of the flag, see for example CompleteValues.
+	// If nil, the Completer implemented by the flag value is used, if any.
+	Complete CompleteFunc
 }
 
+// SecretAnnotation is the [pflag.Flag] annotation marking flags registered with RegisterOptions.Secret.
+const SecretAnnotation = "nagini_flag_secret"
+
 // RegisterModifier tweak RegisterOptions.
 type RegisterModifier func(options *RegisterOptions)
 
@@ -46,6 +68,13 @@ func Persistent() RegisterModifier {
 	}
 }
 
+// InGroup sets the group of the flag shown in the help output as a RegisterModifier.
+func InGroup(group string) RegisterModifier {
+	return func(options *RegisterOptions) {
+		options.Group = group
+	}
+}
+
 // SelectFlags is used when registering parameters. See command package.
 func (o RegisterOptions) SelectFlags(cmd *cobra.Command) *pflag.FlagSet {
 	if o.Persistent {
@@ -61,7 +90,7 @@ func (o RegisterOptions) AfterRegistration(cmd *cobra.Command, flag *pflag.Flag,
 	if binding, ok := value.(Binding); ok {
 		if binder := binding.BindTo(); binder != nil {
 			action := func(*cobra.Command, []string) error {
-				return binder(flag)
+				return bind(binder, flag)
 			}
 			if o.Persistent {
 				addToCobraRun(&cmd.PersistentPreRunE, action)
@@ -72,12 +101,33 @@ func (o RegisterOptions) AfterRegistration(cmd *cobra.Command, flag *pflag.Flag,
 	}
 	flag.Deprecated = o.Deprecated
 	flag.Hidden = o.Hidden
+	if o.Group != "" {
+		if flag.Annotations == nil {
+			flag.Annotations = map[string][]string{}
+		}
+		flag.Annotations[GroupAnnotation] = []string{o.Group}
+	}
 	if o.Required {
 		_ = cmd.MarkFlagRequired(flag.Name)
 	}
+	if o.Secret {
+		if flag.Annotations == nil {
+			flag.Annotations = map[string][]string{}
+		}
+		flag.Annotations[SecretAnnotation] = []string{"true"}
+	}
 	if value.IsBoolFlag() {
 		flag.NoOptDefVal = "true"
+		if o.Negatable {
+			registerNegation(o.SelectFlags(cmd), flag, value)
+		}
+	}
+	registerAliases(o.SelectFlags(cmd), flag, value, o.Aliases)
+	flagNames := []string{flag.Name}
+	for _, alias := range o.Aliases {
+		flagNames = append(flagNames, alias.Name)
 	}
+	o.registerCompletion(cmd, value, flagNames...)
 }
 
 // Apply applies the given RegisterModifier's to this instance of RegisterOptions.
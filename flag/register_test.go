@@ -12,6 +12,11 @@ func TestWithUsage(t *testing.T) {
 	assert.Equal(t, "some usage", options.Usage)
 }
 
+func TestInGroup(t *testing.T) {
+	options := RegisterOptions{}.Apply(InGroup("Connection"))
+	assert.Equal(t, "Connection", options.Group)
+}
+
 func TestPersistent(t *testing.T) {
 	options := RegisterOptions{}.Apply(Persistent())
 	cmd := &cobra.Command{}
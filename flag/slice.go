@@ -1,7 +1,6 @@
 package flag
 
 import (
-	"bytes"
 	"encoding/csv"
 	"fmt"
 	"reflect"
@@ -69,17 +68,24 @@ func readAsCSV(val string) ([]string, error) {
 	csvReader := csv.NewReader(stringReader)
 	result, err := csvReader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("cannot read value '%s' as comma-separated values: %w", val, err)
+		return nil, fmt.Errorf("%w: cannot read value '%s' as comma-separated values: %w", ErrParser, val, err)
 	}
 	return result, nil
 }
 
+// asCsv writes the elements as comma-separated values, such that they can be read again by Set.
+// In contrast to [csv.Writer], also quotes empty elements and elements containing brackets,
+// as Set trims brackets and an empty string is read as an empty slice.
 func (v anySliceValue[T, E]) asCsv() string {
-	var buffer bytes.Buffer
-	csvWriter := csv.NewWriter(&buffer)
-	_ = csvWriter.Write(v.GetSlice())
-	csvWriter.Flush()
-	return strings.TrimSpace(buffer.String())
+	fields := v.GetSlice()
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field == "" || strings.ContainsAny(field, ",\"[]\r\n") || strings.TrimSpace(field) != field {
+			field = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+		}
+		quoted = append(quoted, field)
+	}
+	return strings.Join(quoted, ",")
 }
 
 func (v anySliceValue[T, E]) Set(s string) error {
@@ -107,7 +107,8 @@ func Test_readAsCSV(t *testing.T) {
 		{"one value", "some value with spaces", []string{"some value with spaces"}, assert.NoError},
 		{"two values", "val2,val1", []string{"val2", "val1"}, assert.NoError},
 		{"parsing fails", `",`, nil, func(t assert.TestingT, err error, args ...any) bool {
-			return assert.ErrorContains(t, err, `cannot read value '",' as comma-separated values`, args...)
+			return assert.ErrorContains(t, err, `cannot read value '",' as comma-separated values`, args...) &&
+				assert.ErrorIs(t, err, ErrParser, args...)
 		}},
 	}
 	for _, tt := range tests {
@@ -120,3 +121,30 @@ func Test_readAsCSV(t *testing.T) {
 		})
 	}
 }
+
+func Test_anySliceValue_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		target []string
+		want   string
+	}{
+		{"nil", nil, "<nil>"},
+		{"empty", []string{}, "<empty>"},
+		{"plain values", []string{"val1", "val2"}, "val1,val2"},
+		{"empty value", []string{""}, `""`},
+		{"values with commas and quotes", []string{"a,b", `c"d`}, `"a,b","c""d"`},
+		{"values with brackets", []string{"[a", "b]"}, `"[a","b]"`},
+		{"values with surrounding spaces", []string{" a", "b "}, `" a","b "`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := Slice(&tt.target, ParseSliceOf[string](AnyString))
+			assert.Equal(t, tt.want, sut.String())
+			if len(tt.target) > 0 {
+				var roundTripped []string
+				require.NoError(t, Slice(&roundTripped, ParseSliceOf[string](AnyString)).Set(sut.String()))
+				assert.Equal(t, tt.target, roundTripped)
+			}
+		})
+	}
+}
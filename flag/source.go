@@ -0,0 +1,65 @@
+package flag
+
+import "github.com/spf13/pflag"
+
+// Source describes where the value of a flag originates from.
+type Source string
+
+const (
+	// SourceDefault is used if the flag value has not been changed since registration.
+	SourceDefault Source = "default"
+	// SourceFlag is used if the flag value has been set from the command line.
+	SourceFlag Source = "flag"
+	// SourceEnv is used if the flag value has been set from an environment variable.
+	SourceEnv Source = "env"
+	// SourceConfig is used if the flag value has been set from a configuration file.
+	SourceConfig Source = "config"
+	// SourcePrompt is used if the flag value has been entered interactively,
+	// see [github.com/neiser/go-nagini/command.WithPrompting].
+	SourcePrompt Source = "prompt"
+)
+
+// SourceAnnotation is the [pflag.Flag] annotation holding the Source of a value set by a Binding, see SetSource.
+const SourceAnnotation = "nagini_flag_source"
+
+// SetSource records the Source of the value of the given flag.
+// A Binder should call it after setting the value from a source other than the command line.
+// See for example Env.
+func SetSource(flag *pflag.Flag, source Source) {
+	if flag.Annotations == nil {
+		flag.Annotations = map[string][]string{}
+	}
+	flag.Annotations[SourceAnnotation] = []string{string(source)}
+}
+
+// SourceOf returns the Source of the value of the given flag.
+// That is SourceFlag if the flag has been changed on the command line,
+// the source recorded with SetSource, or SourceDefault otherwise.
+// As an exception, SourcePrompt is returned for changed flags, as prompted values are marked as changed
+// to satisfy required flags.
+func SourceOf(flag *pflag.Flag) Source {
+	source := flag.Annotations[SourceAnnotation]
+	if flag.Changed && (len(source) == 0 || Source(source[0]) != SourcePrompt) {
+		return SourceFlag
+	}
+	if len(source) > 0 {
+		return Source(source[0])
+	}
+	return SourceDefault
+}
+
+// SourceError annotates an error concerning a flag value with the Source of that value.
+// The error message is not modified.
+// See for example [github.com/neiser/go-nagini/flag/binding.Viper].
+type SourceError struct {
+	Source  Source
+	Wrapped error
+}
+
+func (e SourceError) Unwrap() error {
+	return e.Wrapped
+}
+
+func (e SourceError) Error() string {
+	return e.Wrapped.Error()
+}
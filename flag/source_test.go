@@ -0,0 +1,24 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceOf(t *testing.T) {
+	f := &pflag.Flag{}
+	assert.Equal(t, SourceDefault, SourceOf(f))
+	SetSource(f, SourceConfig)
+	assert.Equal(t, SourceConfig, SourceOf(f))
+	f.Changed = true
+	assert.Equal(t, SourceFlag, SourceOf(f))
+}
+
+func TestSourceOf_prompt(t *testing.T) {
+	f := &pflag.Flag{}
+	SetSource(f, SourcePrompt)
+	f.Changed = true
+	assert.Equal(t, SourcePrompt, SourceOf(f))
+}
@@ -0,0 +1,264 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// StructField is a flag derived from a struct field by FromStruct.
+type StructField struct {
+	Value   Value
+	Options RegisterOptions
+}
+
+// StructOption customizes FromStruct.
+type StructOption func(options *structOptions)
+
+type structOptions struct {
+	constructors map[reflect.Type]func(target any) Value
+	bindConfig   func(value Value, configKey string) Value
+}
+
+// ParserFor registers the Parser for fields of type T and of type []T, see FromStruct.
+// It takes precedence over the built-in parsers for the same type.
+func ParserFor[T any](parser Parser[T]) StructOption {
+	return func(options *structOptions) {
+		options.constructors[reflect.TypeFor[T]()] = func(target any) Value {
+			return String(target.(*T), parser) //nolint:forcetypeassert
+		}
+		options.constructors[reflect.TypeFor[[]T]()] = func(target any) Value {
+			return Slice(target.(*[]T), ParseSliceOf(parser)) //nolint:forcetypeassert
+		}
+	}
+}
+
+// WithConfigBinding binds fields with a config tag using the given function, see FromStruct.
+// For example, use [github.com/neiser/go-nagini/flag/binding.ViperConfig] to bind to Viper.
+func WithConfigBinding(bind func(value Value, configKey string) Value) StructOption {
+	return func(options *structOptions) {
+		options.bindConfig = bind
+	}
+}
+
+// ErrInvalidStruct is returned by FromStruct for unsupported structs.
+var ErrInvalidStruct = errors.New("invalid struct for flags")
+
+// FromStruct returns one StructField per exported field of the struct the given target points to.
+// The current field values are the defaults of the flags. The following struct tags are supported:
+//
+//   - flag: The flag name, defaults to the field name in kebab-case. Use "-" to skip the field.
+//   - short: The shorthand of the flag.
+//   - usage: The usage of the flag.
+//   - group: The group shown in the help output, see RegisterOptions.Group.
+//   - required, hidden, persistent, negatable, secret: Set the corresponding RegisterOptions, if "true".
+//   - env: Binds the flag to the environment variable, see Env.
+//   - config: Binds the flag to the config key, see WithConfigBinding.
+//   - enum: Comma-separated allowed values for string fields, see Enum.
+//   - default: The default value of the flag, set during FromStruct instead of the current field value.
+//
+// Fields of type string, bool, int, int64, uint, uint64, float64, [time.Duration], slices of those,
+// and types based on them are supported.
+// Use ParserFor to support other types. Alternatively, a pointer to the field type may implement TargetParser.
+// Fields of nested structs become flags prefixed with the name of the struct field, such as "db-host",
+// unless the struct is embedded.
+func FromStruct(target any, options ...StructOption) ([]StructField, error) {
+	opts := structOptions{constructors: builtinConstructors()}
+	for _, option := range options {
+		option(&opts)
+	}
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: target must be pointer to struct, but is %T", ErrInvalidStruct, target)
+	}
+	return opts.fromStruct(targetValue.Elem(), "")
+}
+
+func (o structOptions) fromStruct(structValue reflect.Value, prefix string) (result []StructField, err error) {
+	for i := range structValue.NumField() {
+		field := structValue.Type().Field(i)
+		name, found := field.Tag.Lookup("flag")
+		// exported fields of embedded structs are promoted even if the struct type is unexported
+		embeddedStruct := field.Anonymous && field.Type.Kind() == reflect.Struct
+		if !field.IsExported() && !embeddedStruct || name == "-" {
+			continue
+		}
+		if !found {
+			name = kebabCase(field.Name)
+		}
+		fieldValue := structValue.Field(i)
+		var value Value
+		if !embeddedStruct {
+			if value, err = o.newValue(field, fieldValue.Addr()); err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		if value == nil {
+			nestedPrefix := prefix + name + "-"
+			if embeddedStruct {
+				nestedPrefix = prefix
+			}
+			nested, err := o.fromStruct(fieldValue, nestedPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			result = append(result, nested...)
+			continue
+		}
+		if defaultValue, found := field.Tag.Lookup("default"); found {
+			if err := value.Set(defaultValue); err != nil {
+				return nil, fmt.Errorf("field %s: %w: invalid default tag: %w", field.Name, ErrInvalidStruct, err)
+			}
+		}
+		registerOptions, err := o.registerOptions(field, prefix+name)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if configKey := field.Tag.Get("config"); configKey != "" {
+			if o.bindConfig == nil {
+				return nil, fmt.Errorf("field %s: %w: config tag requires WithConfigBinding", field.Name, ErrInvalidStruct)
+			}
+			value = o.bindConfig(value, configKey)
+		}
+		if envVar := field.Tag.Get("env"); envVar != "" {
+			value = Env{value, envVar}
+		}
+		result = append(result, StructField{value, registerOptions})
+	}
+	return
+}
+
+// newValue constructs the Value for the given field, or returns nil if the field is a nested struct.
+func (o structOptions) newValue(field reflect.StructField, target reflect.Value) (Value, error) {
+	if enum, found := field.Tag.Lookup("enum"); found {
+		stringTarget, ok := convertTarget(target, reflect.TypeFor[*string]())
+		if !ok {
+			return nil, fmt.Errorf("%w: enum tag requires string type, but is %s", ErrInvalidStruct, field.Type)
+		}
+		return Enum(stringTarget.(*string), strings.Split(enum, ",")...), nil //nolint:forcetypeassert
+	}
+	if constructor, found := o.constructors[field.Type]; found {
+		return constructor(target.Interface()), nil
+	}
+	if targetParser, ok := target.Interface().(TargetParser); ok {
+		return targetParserValue{target, targetParser}, nil
+	}
+	for valueType, constructor := range o.constructors {
+		if convertedTarget, ok := convertTarget(target, reflect.PointerTo(valueType)); ok &&
+			valueType.PkgPath() == "" && field.Type.Kind() == valueType.Kind() {
+			return constructor(convertedTarget), nil
+		}
+	}
+	if field.Type.Kind() == reflect.Struct && hasExportedFields(field.Type) {
+		return nil, nil //nolint:nilnil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %s, use ParserFor to add support", ErrInvalidStruct, field.Type)
+}
+
+func (o structOptions) registerOptions(field reflect.StructField, name string) (RegisterOptions, error) {
+	options := RegisterOptions{
+		Name:      name,
+		Shorthand: field.Tag.Get("short"),
+		Usage:     field.Tag.Get("usage"),
+		Group:     field.Tag.Get("group"),
+	}
+	for tag, option := range map[string]*bool{
+		"required":   &options.Required,
+		"hidden":     &options.Hidden,
+		"persistent": &options.Persistent,
+		"negatable":  &options.Negatable,
+		"secret":     &options.Secret,
+	} {
+		if value, found := field.Tag.Lookup(tag); found {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return options, fmt.Errorf("%w: invalid %s tag: %w", ErrInvalidStruct, tag, err)
+			}
+			*option = parsed
+		}
+	}
+	return options, nil
+}
+
+func hasExportedFields(structType reflect.Type) bool {
+	for i := range structType.NumField() {
+		if structType.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// convertTarget converts the target pointer to the given pointer type, if the underlying types are identical.
+func convertTarget(target reflect.Value, pointerType reflect.Type) (any, bool) {
+	if !target.Type().ConvertibleTo(pointerType) {
+		return nil, false
+	}
+	return target.Convert(pointerType).Interface(), true
+}
+
+func builtinConstructors() map[reflect.Type]func(target any) Value {
+	options := structOptions{constructors: map[reflect.Type]func(target any) Value{}}
+	for _, option := range []StructOption{
+		ParserFor(NotEmpty[string]),
+		ParserFor(strconv.ParseBool),
+		ParserFor(strconv.Atoi),
+		ParserFor(func(s string) (int64, error) { return strconv.ParseInt(s, 0, 64) }),
+		ParserFor(func(s string) (uint, error) {
+			parsed, err := strconv.ParseUint(s, 0, strconv.IntSize)
+			return uint(parsed), err
+		}),
+		ParserFor(func(s string) (uint64, error) { return strconv.ParseUint(s, 0, 64) }),
+		ParserFor(func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }),
+		ParserFor(time.ParseDuration),
+	} {
+		option(&options)
+	}
+	return options.constructors
+}
+
+// kebabCase converts a Go field name, such as "DBHost", to kebab-case, such as "db-host".
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			builder.WriteRune('-')
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// targetParserValue supports struct fields implementing TargetParser, see FromStruct.
+// Uses reflection, as String cannot be instantiated for field types only known at runtime.
+type targetParserValue struct {
+	target       reflect.Value
+	targetParser TargetParser
+}
+
+func (v targetParserValue) Target() any {
+	return v.target.Interface()
+}
+
+func (v targetParserValue) String() string {
+	return convertToString(v.target.Elem().Interface())
+}
+
+//nolint:wrapcheck
+func (v targetParserValue) Set(s string) error {
+	return v.targetParser.Parse(s)
+}
+
+func (v targetParserValue) Type() string {
+	return v.target.Elem().Type().String()
+}
+
+func (v targetParserValue) IsBoolFlag() bool {
+	return v.target.Elem().Kind() == reflect.Bool
+}
@@ -0,0 +1,197 @@
+package flag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type someLevel string
+
+type someUpperString string
+
+func (s *someUpperString) Parse(value string) error {
+	*s = someUpperString(strings.ToUpper(value))
+	return nil
+}
+
+type someEmbedded struct {
+	Verbose bool `short:"v" usage:"Be verbose" persistent:"true"`
+}
+
+type someOptions struct {
+	someEmbedded
+
+	Name     string        `usage:"The name" required:"true"`
+	Level    someLevel     `enum:"debug,info"`
+	Timeout  time.Duration `env:"SOME_TIMEOUT"`
+	Retries  uint
+	Ratio    float64 `flag:"the-ratio" hidden:"true" group:"Tuning"`
+	Tags     []string
+	Upper    someUpperString
+	Ignored  string `flag:"-"`
+	unexport string
+	DB       struct {
+		Host string `config:"db.host"`
+		Port int
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	opts := someOptions{Name: "default", Retries: 3}
+	fields, err := FromStruct(&opts, WithConfigBinding(func(value Value, configKey string) Value {
+		return someBinding{value, nil}
+	}))
+	require.NoError(t, err)
+	var names []string
+	options := map[string]RegisterOptions{}
+	values := map[string]Value{}
+	for _, field := range fields {
+		names = append(names, field.Options.Name)
+		options[field.Options.Name] = field.Options
+		values[field.Options.Name] = field.Value
+	}
+	assert.Equal(t, []string{"verbose", "name", "level", "timeout", "retries", "the-ratio", "tags", "upper", "db-host", "db-port"}, names)
+
+	assert.Equal(t, RegisterOptions{Name: "verbose", Shorthand: "v", Usage: "Be verbose", Persistent: true}, options["verbose"])
+	assert.Equal(t, RegisterOptions{Name: "name", Usage: "The name", Required: true}, options["name"])
+	assert.Equal(t, RegisterOptions{Name: "the-ratio", Hidden: true, Group: "Tuning"}, options["the-ratio"])
+
+	t.Run("values are set on struct", func(t *testing.T) {
+		for name, value := range map[string]string{
+			"verbose": "true", "name": "some name", "level": "info", "timeout": "1m", "retries": "5",
+			"the-ratio": "0.5", "tags": "a,b", "upper": "up", "db-host": "localhost", "db-port": "5432",
+		} {
+			require.NoError(t, values[name].Set(value), name)
+		}
+		assert.Equal(t, someOptions{
+			someEmbedded: someEmbedded{Verbose: true},
+			Name:         "some name",
+			Level:        "info",
+			Timeout:      time.Minute,
+			Retries:      5,
+			Ratio:        0.5,
+			Tags:         []string{"a", "b"},
+			Upper:        "UP",
+			DB: struct {
+				Host string `config:"db.host"`
+				Port int
+			}{"localhost", 5432},
+		}, opts)
+	})
+
+	t.Run("values are validated", func(t *testing.T) {
+		require.ErrorIs(t, values["name"].Set(""), ErrParser)
+		require.ErrorIs(t, values["level"].Set("trace"), ErrParser)
+		require.Error(t, values["retries"].Set("-1"))
+	})
+
+	t.Run("default values", func(t *testing.T) {
+		opts := someOptions{Name: "default"}
+		fields, err := FromStruct(&opts, WithConfigBinding(func(value Value, _ string) Value { return value }))
+		require.NoError(t, err)
+		assert.Equal(t, "default", fields[1].Value.String())
+	})
+
+	t.Run("default tag", func(t *testing.T) {
+		opts := struct {
+			Count int      `default:"3"`
+			Tags  []string `default:"a,b"`
+		}{Count: 1}
+		fields, err := FromStruct(&opts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, opts.Count)
+		assert.Equal(t, []string{"a", "b"}, opts.Tags)
+		assert.Equal(t, "a,b", fields[1].Value.String())
+	})
+
+	t.Run("bindings", func(t *testing.T) {
+		env, ok := values["timeout"].(Env)
+		require.True(t, ok)
+		assert.Equal(t, "SOME_TIMEOUT", env.EnvVar)
+		_, ok = values["db-host"].(someBinding)
+		assert.True(t, ok)
+	})
+
+	t.Run("types", func(t *testing.T) {
+		assert.Equal(t, "[]string", values["tags"].Type())
+		assert.Equal(t, "flag.someUpperString", values["upper"].Type())
+		enum, ok := ValueAs[EnumValue](values["level"])
+		require.True(t, ok)
+		assert.Equal(t, []string{"debug", "info"}, enum.EnumValues())
+		assert.True(t, values["verbose"].IsBoolFlag())
+	})
+}
+
+func TestFromStruct_parserFor(t *testing.T) {
+	type someIP [4]byte
+	var opts struct {
+		IP  someIP
+		IPs []someIP
+	}
+	parser := func(s string) (someIP, error) {
+		if s != "localhost" {
+			return someIP{}, errors.New("unknown host")
+		}
+		return someIP{127, 0, 0, 1}, nil
+	}
+	fields, err := FromStruct(&opts, ParserFor(parser))
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	require.NoError(t, fields[0].Value.Set("localhost"))
+	require.NoError(t, fields[1].Value.Set("localhost,localhost"))
+	assert.Equal(t, someIP{127, 0, 0, 1}, opts.IP)
+	assert.Equal(t, []someIP{{127, 0, 0, 1}, {127, 0, 0, 1}}, opts.IPs)
+}
+
+func TestFromStruct_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  any
+		wantErr string
+	}{
+		{"no pointer", struct{}{}, "invalid struct for flags: target must be pointer to struct, but is struct {}"},
+		{"no struct", new(string), "invalid struct for flags: target must be pointer to struct, but is *string"},
+		{"unsupported type", &struct{ Some *int }{},
+			"field Some: invalid struct for flags: unsupported type *int, use ParserFor to add support"},
+		{"struct without exported fields", &struct{ Some time.Time }{},
+			"field Some: invalid struct for flags: unsupported type time.Time, use ParserFor to add support"},
+		{"enum on non-string", &struct {
+			Some int `enum:"1,2"`
+		}{}, "field Some: invalid struct for flags: enum tag requires string type, but is int"},
+		{"invalid bool tag", &struct {
+			Some int `required:"yes"`
+		}{}, `field Some: invalid struct for flags: invalid required tag: strconv.ParseBool: parsing "yes": invalid syntax`},
+		{"invalid default tag", &struct {
+			Some int `default:"x"`
+		}{}, `field Some: invalid struct for flags: invalid default tag: strconv.Atoi: parsing "x": invalid syntax`},
+		{"config without binding", &struct {
+			Some int `config:"some"`
+		}{}, "field Some: invalid struct for flags: config tag requires WithConfigBinding"},
+		{"nested error", &struct{ Some struct{ Other *int } }{},
+			"field Some: field Other: invalid struct for flags: unsupported type *int, use ParserFor to add support"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromStruct(tt.target)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_kebabCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"Name":       "name",
+		"MaxRetries": "max-retries",
+		"DBHost":     "db-host",
+		"HTTPServer": "http-server",
+		"UseHTTP":    "use-http",
+		"IPv6":       "i-pv6",
+	} {
+		assert.Equal(t, want, kebabCase(name), name)
+	}
+}
@@ -14,3 +14,26 @@ type Value interface {
 	// See also Bool.
 	IsBoolFlag() bool
 }
+
+// WrappingValue is implemented by a Value wrapping another Value, such as bindings.
+// See ValueAs.
+type WrappingValue interface {
+	Value
+	Unwrap() Value
+}
+
+// ValueAs finds the first value in the chain of wrapped values (see WrappingValue) which has type T.
+// Similar to [errors.As], this allows to find out if a flag value implements optional interfaces, such as EnumValue.
+func ValueAs[T any](value pflag.Value) (result T, ok bool) {
+	for value != nil {
+		if result, ok = value.(T); ok {
+			return
+		}
+		wrappingValue, isWrapping := value.(WrappingValue)
+		if !isWrapping {
+			return
+		}
+		value = wrappingValue.Unwrap()
+	}
+	return
+}
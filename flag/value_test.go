@@ -0,0 +1,36 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueAs(t *testing.T) {
+	var (
+		target string
+	)
+	value := String(&target, NotEmpty)
+	t.Run("direct", func(t *testing.T) {
+		found, ok := ValueAs[Value](value)
+		assert.True(t, ok)
+		assert.Same(t, &target, found.Target())
+	})
+	t.Run("wrapped", func(t *testing.T) {
+		found, ok := ValueAs[EnumValue](someWrappingValue{Enum(&target, "a")})
+		assert.True(t, ok)
+		assert.Equal(t, []string{"a"}, found.EnumValues())
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, ok := ValueAs[EnumValue](someWrappingValue{value})
+		assert.False(t, ok)
+	})
+}
+
+type someWrappingValue struct {
+	Value
+}
+
+func (v someWrappingValue) Unwrap() Value {
+	return v.Value
+}